@@ -0,0 +1,94 @@
+package badges
+
+import "example.com/m/events"
+
+// Rule says what earns a badge: Target distinct places recorded by Event
+// (visits or saves) that match every filter that is set.
+type Rule struct {
+	Event  events.Kind
+	Target int
+	// Categories matches Geoapify categories and their subcategories.
+	Categories []string
+	// States matches the place's state as the catalog has it.
+	States []string
+	// VerifiedOnly counts only verified visits; Method only visits verified
+	// that way (gps or qr).
+	VerifiedOnly bool
+	Method       string
+}
+
+type Badge struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Rule        Rule   `json:"-"`
+}
+
+// Badges is every badge a user can earn. Keys are stored in user_badges, so
+// never rename one; retire it instead.
+var Badges = []Badge{
+	{
+		Key:         "first_visit",
+		Name:        "First Steps",
+		Description: "Log your first visit",
+		Rule:        Rule{Event: events.Visit, Target: 1},
+	},
+	{
+		Key:         "first_save",
+		Name:        "Wishlist",
+		Description: "Save your first place",
+		Rule:        Rule{Event: events.Save, Target: 1},
+	},
+	{
+		Key:         "first_checkin",
+		Name:        "On the Spot",
+		Description: "Check in with a site's QR code",
+		Rule:        Rule{Event: events.Visit, Target: 1, VerifiedOnly: true, Method: "qr"},
+	},
+	{
+		Key:         "explorer_10",
+		Name:        "Explorer",
+		Description: "Visit 10 different places",
+		Rule:        Rule{Event: events.Visit, Target: 10, VerifiedOnly: true},
+	},
+	{
+		Key:         "odisha_temples_3",
+		Name:        "Temple Trail",
+		Description: "Visit 3 temples in Odisha",
+		Rule: Rule{
+			Event:        events.Visit,
+			Target:       3,
+			Categories:   []string{"religion.place_of_worship.hinduism"},
+			States:       []string{"Odisha"},
+			VerifiedOnly: true,
+		},
+	},
+	{
+		Key:         "museums_5",
+		Name:        "Curator",
+		Description: "Visit 5 museums",
+		Rule: Rule{
+			Event:        events.Visit,
+			Target:       5,
+			Categories:   []string{"entertainment.museum"},
+			VerifiedOnly: true,
+		},
+	},
+	{
+		Key:         "unesco_5",
+		Name:        "World Heritage Seeker",
+		Description: "Visit 5 UNESCO World Heritage sites",
+		Rule: Rule{
+			Event:        events.Visit,
+			Target:       5,
+			Categories:   []string{"heritage.unesco"},
+			VerifiedOnly: true,
+		},
+	},
+	{
+		Key:         "collector_25",
+		Name:        "Collector",
+		Description: "Save 25 places",
+		Rule:        Rule{Event: events.Save, Target: 25},
+	},
+}
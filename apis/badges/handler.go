@@ -0,0 +1,43 @@
+package badges
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"example.com/m/i18n"
+	"example.com/m/utils"
+)
+
+type BadgesResponse struct {
+	Earned []BadgeStatus `json:"earned"`
+	Locked []BadgeStatus `json:"locked"`
+}
+
+// ListHandler lists the user's badges
+// @Summary List Badges
+// @Description Every badge, split into those the user has earned and those still locked, with progress toward each
+// @Tags useractions
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} BadgesResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/badges [get]
+func ListHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	earned, locked, err := UserBadges(r.Context(), userUUID)
+	if err != nil {
+		log.Printf("Listing badges failed: %v", err)
+		i18n.Error(w, r, i18n.GetBadgesFailed, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(BadgesResponse{Earned: earned, Locked: locked})
+}
@@ -0,0 +1,179 @@
+package badges
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"example.com/m/db"
+	"example.com/m/events"
+)
+
+// Init evaluates badges whenever a user visits or saves a place.
+func Init() {
+	events.Subscribe(events.Visit, evaluate)
+	events.Subscribe(events.Save, evaluate)
+}
+
+// progress counts the distinct places the user has that satisfy rule.
+func progress(ctx context.Context, userUUID string, rule Rule) (int, error) {
+	query, args := progressQuery(userUUID, rule)
+	var n int
+	err := db.Conn.QueryRow(ctx, query, args...).Scan(&n)
+	return n, err
+}
+
+// progressQuery builds the count behind progress.
+func progressQuery(userUUID string, rule Rule) (string, []any) {
+	table := "user_visit_history"
+	if rule.Event == events.Save {
+		table = "user_saved_places"
+	}
+
+	args := []any{userUUID}
+	query := `SELECT COUNT(DISTINCT t.place_id)
+		 FROM ` + table + ` t LEFT JOIN places p ON p.place_id = t.place_id
+		 WHERE t.user_uuid=$1`
+
+	if rule.Event == events.Visit {
+		if rule.VerifiedOnly {
+			query += ` AND t.verified`
+		}
+		if rule.Method != "" {
+			args = append(args, rule.Method)
+			query += fmt.Sprintf(` AND t.verification_method = $%d`, len(args))
+		}
+	}
+	if len(rule.Categories) > 0 {
+		args = append(args, rule.Categories)
+		query += fmt.Sprintf(
+			` AND EXISTS (SELECT 1 FROM unnest(p.categories) c, unnest($%d::text[]) want
+				WHERE c = want OR c LIKE want || '.%%')`, len(args))
+	}
+	if len(rule.States) > 0 {
+		args = append(args, rule.States)
+		query += fmt.Sprintf(` AND p.state = ANY($%d::text[])`, len(args))
+	}
+	return query, args
+}
+
+// advances reports whether e could move rule's count: it's the right kind
+// of event, and a visit was verified the way the rule asks.
+func advances(rule Rule, e events.Event) bool {
+	if rule.Event != e.Kind {
+		return false
+	}
+	if e.Kind == events.Visit && (rule.VerifiedOnly && !e.Verified || rule.Method != "" && rule.Method != e.Method) {
+		return false
+	}
+	return true
+}
+
+// earned returns when the user earned each badge they have.
+func earned(ctx context.Context, userUUID string) (map[string]time.Time, error) {
+	rows, err := db.Conn.Query(ctx,
+		`SELECT badge_key, awarded_at FROM user_badges
+		 WHERE user_uuid=$1 AND badge_key IS NOT NULL`,
+		userUUID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	awarded := map[string]time.Time{}
+	for rows.Next() {
+		var key string
+		var at time.Time
+		if err := rows.Scan(&key, &at); err == nil {
+			awarded[key] = at
+		}
+	}
+	return awarded, rows.Err()
+}
+
+// award records a badge, reporting false if the user already had it.
+func award(ctx context.Context, userUUID, key string) (bool, error) {
+	tag, err := db.Conn.Exec(ctx,
+		`INSERT INTO user_badges (user_uuid, badge_key) VALUES ($1, $2)
+		 ON CONFLICT (user_uuid, badge_key) DO NOTHING`,
+		userUUID, key,
+	)
+	return tag.RowsAffected() == 1, err
+}
+
+// evaluate awards any badge the event may have completed.
+func evaluate(ctx context.Context, e events.Event) {
+	awarded, err := earned(ctx, e.UserUUID)
+	if err != nil {
+		log.Printf("Loading badges failed: %v", err)
+		return
+	}
+
+	for _, b := range Badges {
+		rule := b.Rule
+		if !advances(rule, e) {
+			continue
+		}
+		if _, ok := awarded[b.Key]; ok {
+			continue
+		}
+
+		n, err := progress(ctx, e.UserUUID, rule)
+		if err != nil {
+			log.Printf("Badge %s progress failed: %v", b.Key, err)
+			continue
+		}
+		if n < rule.Target {
+			continue
+		}
+
+		ok, err := award(ctx, e.UserUUID, b.Key)
+		if err != nil {
+			log.Printf("Awarding badge %s failed: %v", b.Key, err)
+			continue
+		}
+		if ok {
+			events.Publish(ctx, events.Event{Kind: events.BadgeAwarded, UserUUID: e.UserUUID, Ref: b.Key})
+		}
+	}
+}
+
+// BadgeStatus is a badge as one user sees it.
+type BadgeStatus struct {
+	Badge
+	Earned    bool       `json:"earned"`
+	AwardedAt *time.Time `json:"awarded_at,omitempty"`
+	Progress  int        `json:"progress"`
+	Target    int        `json:"target"`
+}
+
+// UserBadges returns the user's earned and locked badges, with progress
+// toward the locked ones.
+func UserBadges(ctx context.Context, userUUID string) (earnedList, locked []BadgeStatus, err error) {
+	awarded, err := earned(ctx, userUUID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	earnedList, locked = []BadgeStatus{}, []BadgeStatus{}
+	for _, b := range Badges {
+		status := BadgeStatus{Badge: b, Target: b.Rule.Target}
+		if at, ok := awarded[b.Key]; ok {
+			status.Earned = true
+			status.AwardedAt = &at
+			status.Progress = b.Rule.Target
+			earnedList = append(earnedList, status)
+			continue
+		}
+
+		n, err := progress(ctx, userUUID, b.Rule)
+		if err != nil {
+			return nil, nil, err
+		}
+		status.Progress = min(n, b.Rule.Target)
+		locked = append(locked, status)
+	}
+	return earnedList, locked, nil
+}
@@ -0,0 +1,115 @@
+package badges
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"example.com/m/events"
+)
+
+func TestAdvances(t *testing.T) {
+	visit := func(verified bool, method string) events.Event {
+		return events.Event{Kind: events.Visit, UserUUID: "u", PlaceID: "p", Verified: verified, Method: method}
+	}
+	save := events.Event{Kind: events.Save, UserUUID: "u", PlaceID: "p"}
+
+	anyVisit := Rule{Event: events.Visit, Target: 1}
+	verified := Rule{Event: events.Visit, Target: 10, VerifiedOnly: true}
+	qr := Rule{Event: events.Visit, Target: 1, VerifiedOnly: true, Method: "qr"}
+	saves := Rule{Event: events.Save, Target: 25}
+
+	tests := []struct {
+		name string
+		rule Rule
+		e    events.Event
+		want bool
+	}{
+		{"any visit, unverified", anyVisit, visit(false, ""), true},
+		{"any visit, verified", anyVisit, visit(true, "gps"), true},
+		{"verified rule, unverified visit", verified, visit(false, ""), false},
+		{"verified rule, gps visit", verified, visit(true, "gps"), true},
+		{"qr rule, qr visit", qr, visit(true, "qr"), true},
+		{"qr rule, gps visit", qr, visit(true, "gps"), false},
+		{"qr rule, unverified visit", qr, visit(false, ""), false},
+		{"visit rule, save", anyVisit, save, false},
+		{"save rule, save", saves, save, true},
+		{"save rule, visit", saves, visit(true, "gps"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := advances(tt.rule, tt.e); got != tt.want {
+				t.Errorf("advances = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProgressQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     Rule
+		table    string
+		filters  []string
+		wantArgs []any
+	}{
+		{"visits", Rule{Event: events.Visit}, "user_visit_history", nil, []any{"u"}},
+		{"saves", Rule{Event: events.Save}, "user_saved_places", nil, []any{"u"}},
+		{"verified visits", Rule{Event: events.Visit, VerifiedOnly: true}, "user_visit_history",
+			[]string{"AND t.verified"}, []any{"u"}},
+		{"qr visits", Rule{Event: events.Visit, VerifiedOnly: true, Method: "qr"}, "user_visit_history",
+			[]string{"AND t.verified", "AND t.verification_method = $2"}, []any{"u", "qr"}},
+		// Verification means nothing for saves.
+		{"saves ignore verification", Rule{Event: events.Save, VerifiedOnly: true, Method: "qr"}, "user_saved_places", nil, []any{"u"}},
+		{"temples in odisha", Rule{Event: events.Visit, VerifiedOnly: true,
+			Categories: []string{"religion.place_of_worship.hinduism"}, States: []string{"Odisha"}}, "user_visit_history",
+			[]string{"AND t.verified", "unnest($2::text[])", "c LIKE want || '.%'", "AND p.state = ANY($3::text[])"},
+			[]any{"u", []string{"religion.place_of_worship.hinduism"}, []string{"Odisha"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := progressQuery("u", tt.rule)
+			if !strings.Contains(query, "FROM "+tt.table+" t") {
+				t.Errorf("query doesn't read %s:\n%s", tt.table, query)
+			}
+			for _, f := range tt.filters {
+				if !strings.Contains(query, f) {
+					t.Errorf("query lacks %q:\n%s", f, query)
+				}
+			}
+			if tt.filters == nil && strings.Contains(query, " AND ") {
+				t.Errorf("query filters more than the user:\n%s", query)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestBadgeDefinitions(t *testing.T) {
+	seen := map[string]bool{}
+	for _, b := range Badges {
+		if b.Key == "" || b.Name == "" || b.Description == "" {
+			t.Errorf("badge %+v is missing a key, name or description", b)
+		}
+		if seen[b.Key] {
+			t.Errorf("badge key %q is used twice", b.Key)
+		}
+		seen[b.Key] = true
+
+		r := b.Rule
+		if r.Event != events.Visit && r.Event != events.Save {
+			t.Errorf("badge %s counts %q, want visits or saves", b.Key, r.Event)
+		}
+		if r.Target < 1 {
+			t.Errorf("badge %s has target %d", b.Key, r.Target)
+		}
+		if r.Event == events.Save && (r.VerifiedOnly || r.Method != "") {
+			t.Errorf("badge %s asks for verified saves", b.Key)
+		}
+		if r.Method != "" && !r.VerifiedOnly {
+			t.Errorf("badge %s names a method but counts unverified visits", b.Key)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package challenges
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/m/i18n"
+	"example.com/m/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+type CreateChallengeRequest struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Goal        int      `json:"goal"`
+	PlaceIDs    []string `json:"place_ids"`
+	Categories  []string `json:"categories"`
+	// VerifiedOnly defaults to true: only verified visits count.
+	VerifiedOnly *bool      `json:"verified_only,omitempty"`
+	StartsAt     *time.Time `json:"starts_at,omitempty"`
+	EndsAt       *time.Time `json:"ends_at,omitempty"`
+}
+
+type ChallengesResponse struct {
+	Challenges []UserChallenge `json:"challenges"`
+}
+
+// CreateHandler creates a challenge
+// @Summary Create Challenge
+// @Description Define a challenge: visit goal distinct places, from place_ids or categories (any place when both are empty), between starts_at (default now) and ends_at. Admin only.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateChallengeRequest true "Challenge"
+// @Success 201 {object} Challenge
+// @Failure 400 {object} map[string]string "Invalid challenge"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /admin/challenges [post]
+func CreateHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		i18n.Error(w, r, i18n.InvalidJSON, http.StatusBadRequest)
+		return
+	}
+
+	c := Challenge{
+		Title:        strings.TrimSpace(req.Title),
+		Description:  strings.TrimSpace(req.Description),
+		Goal:         req.Goal,
+		PlaceIDs:     req.PlaceIDs,
+		Categories:   req.Categories,
+		VerifiedOnly: req.VerifiedOnly == nil || *req.VerifiedOnly,
+		StartsAt:     time.Now(),
+		EndsAt:       req.EndsAt,
+	}
+	if req.StartsAt != nil {
+		c.StartsAt = *req.StartsAt
+	}
+	if c.PlaceIDs == nil {
+		c.PlaceIDs = []string{}
+	}
+	if c.Categories == nil {
+		c.Categories = []string{}
+	}
+	if c.Title == "" || c.Goal <= 0 || (c.EndsAt != nil && !c.EndsAt.After(c.StartsAt)) {
+		i18n.Error(w, r, i18n.InvalidChallenge, http.StatusBadRequest)
+		return
+	}
+
+	c, err = CreateChallenge(r.Context(), c, userUUID)
+	if err != nil {
+		log.Printf("Creating challenge failed: %v", err)
+		i18n.Error(w, r, i18n.ChallengeFailed, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(c)
+}
+
+// ListHandler lists challenges
+// @Summary List Challenges
+// @Description Challenges still open to join plus every challenge the user joined, with the user's progress
+// @Tags challenges
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Only the user's enrollments in this status (pending, in_progress, completed, expired)"
+// @Success 200 {object} ChallengesResponse
+// @Failure 400 {object} map[string]string "Invalid status"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/challenges [get]
+func ListHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", StatusPending, StatusInProgress, StatusCompleted, StatusExpired:
+	default:
+		i18n.Error(w, r, i18n.InvalidStatus, http.StatusBadRequest)
+		return
+	}
+
+	list, err := ListChallenges(r.Context(), userUUID, status)
+	if err != nil {
+		log.Printf("Listing challenges failed: %v", err)
+		i18n.Error(w, r, i18n.GetChallenges, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ChallengesResponse{Challenges: list})
+}
+
+// challengeID parses the {id} URL parameter, replying 404 when it can't.
+func challengeID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		i18n.Error(w, r, i18n.ChallengeNotFound, http.StatusNotFound)
+		return 0, false
+	}
+	return id, true
+}
+
+// GetHandler returns one challenge
+// @Summary Get Challenge
+// @Description A challenge with the user's enrollment and progress, if they joined
+// @Tags challenges
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Challenge ID"
+// @Success 200 {object} UserChallenge
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Challenge not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/challenges/{id} [get]
+func GetHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, ok := challengeID(w, r)
+	if !ok {
+		return
+	}
+
+	uc, err := GetChallenge(r.Context(), userUUID, id)
+	if err != nil {
+		if errors.Is(err, ErrChallengeNotFound) {
+			i18n.Error(w, r, i18n.ChallengeNotFound, http.StatusNotFound)
+			return
+		}
+		log.Printf("Loading challenge failed: %v", err)
+		i18n.Error(w, r, i18n.GetChallenges, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(uc)
+}
+
+// EnrollHandler joins a challenge
+// @Summary Join Challenge
+// @Description Enroll in a challenge. Visits already made within the challenge window count. Joining again is a no-op.
+// @Tags challenges
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Challenge ID"
+// @Success 200 {object} UserChallenge
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Challenge not found"
+// @Failure 409 {object} map[string]string "Challenge has ended"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/challenges/{id}/enroll [post]
+func EnrollHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, ok := challengeID(w, r)
+	if !ok {
+		return
+	}
+
+	uc, err := Enroll(r.Context(), userUUID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrChallengeNotFound):
+			i18n.Error(w, r, i18n.ChallengeNotFound, http.StatusNotFound)
+		case errors.Is(err, ErrChallengeEnded):
+			i18n.Error(w, r, i18n.ChallengeEnded, http.StatusConflict)
+		default:
+			log.Printf("Enrolling in challenge failed: %v", err)
+			i18n.Error(w, r, i18n.ChallengeFailed, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(uc)
+}
@@ -0,0 +1,69 @@
+package challenges
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/m/middleware"
+)
+
+func withUser(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), middleware.UserUUIDKey, "u"))
+}
+
+func TestCreateHandlerRejectsInvalidChallenges(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not json", `{"title":`},
+		{"no title", `{"goal":3}`},
+		{"blank title", `{"title":"  ","goal":3}`},
+		{"no goal", `{"title":"Temple Run"}`},
+		{"negative goal", `{"title":"Temple Run","goal":-1}`},
+		{"ends before it starts", `{"title":"Temple Run","goal":3,
+			"starts_at":"2026-11-01T00:00:00Z","ends_at":"2026-10-01T00:00:00Z"}`},
+		{"ends as it starts", `{"title":"Temple Run","goal":3,
+			"starts_at":"2026-11-01T00:00:00Z","ends_at":"2026-11-01T00:00:00Z"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := withUser(httptest.NewRequest(http.MethodPost, "/admin/challenges", strings.NewReader(tt.body)))
+			w := httptest.NewRecorder()
+
+			CreateHandler(w, r)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestHandlersNeedAUser(t *testing.T) {
+	handlers := map[string]http.HandlerFunc{
+		"create": CreateHandler, "list": ListHandler, "get": GetHandler, "enroll": EnrollHandler,
+	}
+	for name, h := range handlers {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+}
+
+func TestListHandlerRejectsUnknownStatus(t *testing.T) {
+	for _, status := range []string{"done", "COMPLETED", "in-progress"} {
+		w := httptest.NewRecorder()
+		ListHandler(w, withUser(httptest.NewRequest(http.MethodGet, "/api/user/challenges?status="+status, nil)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("status=%s: got %d, want %d", status, w.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package challenges
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"example.com/m/db"
+	"example.com/m/events"
+	"github.com/jackc/pgx/v5"
+)
+
+// Enrollment statuses.
+const (
+	StatusPending    = "pending"
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+	StatusExpired    = "expired"
+)
+
+var (
+	ErrChallengeNotFound = errors.New("challenge not found")
+	ErrChallengeEnded    = errors.New("challenge has ended")
+)
+
+type Challenge struct {
+	ID           int64      `json:"id"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	Goal         int        `json:"goal"`
+	PlaceIDs     []string   `json:"place_ids"`
+	Categories   []string   `json:"categories"`
+	VerifiedOnly bool       `json:"verified_only"`
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Ended reports whether the challenge's time window has closed.
+func (c Challenge) Ended(now time.Time) bool {
+	return c.EndsAt != nil && !now.Before(*c.EndsAt)
+}
+
+type Enrollment struct {
+	Status      string     `json:"status" enums:"pending,in_progress,completed,expired"`
+	Progress    int        `json:"progress"`
+	EnrolledAt  time.Time  `json:"enrolled_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// UserChallenge is a challenge as one user sees it; Enrollment is nil when
+// they haven't joined.
+type UserChallenge struct {
+	Challenge
+	Enrollment *Enrollment `json:"enrollment,omitempty"`
+}
+
+const challengeColumns = `c.id, c.title, c.description, c.goal, c.place_ids, c.categories,
+	c.verified_only, c.starts_at, c.ends_at, c.created_at`
+
+func challengeDest(c *Challenge) []any {
+	return []any{&c.ID, &c.Title, &c.Description, &c.Goal, &c.PlaceIDs, &c.Categories,
+		&c.VerifiedOnly, &c.StartsAt, &c.EndsAt, &c.CreatedAt}
+}
+
+// Init keeps enrollments up to date as users log visits.
+func Init() {
+	events.Subscribe(events.Visit, onVisit)
+}
+
+func CreateChallenge(ctx context.Context, c Challenge, createdBy string) (Challenge, error) {
+	err := db.Conn.QueryRow(ctx,
+		`INSERT INTO challenges (title, description, goal, place_ids, categories,
+			verified_only, starts_at, ends_at, created_by)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		 RETURNING id, created_at`,
+		c.Title, c.Description, c.Goal, c.PlaceIDs, c.Categories,
+		c.VerifiedOnly, c.StartsAt, c.EndsAt, createdBy,
+	).Scan(&c.ID, &c.CreatedAt)
+	return c, err
+}
+
+// expire marks the user's open enrollments in challenges that have ended.
+func expire(ctx context.Context, userUUID string) error {
+	_, err := db.Conn.Exec(ctx,
+		`UPDATE user_challenges uc SET status=$2, updated_at=NOW()
+		 FROM challenges c
+		 WHERE uc.challenge_id = c.id AND uc.user_uuid=$1
+		   AND uc.status IN ($3, $4) AND c.ends_at <= NOW()`,
+		userUUID, StatusExpired, StatusPending, StatusInProgress,
+	)
+	return err
+}
+
+// ListChallenges returns the challenges still open to join plus every one
+// the user has joined. status, when set, keeps only the user's enrollments
+// in that status.
+func ListChallenges(ctx context.Context, userUUID, status string) ([]UserChallenge, error) {
+	if err := expire(ctx, userUUID); err != nil {
+		return nil, err
+	}
+
+	args := []any{userUUID}
+	filter := `(c.ends_at IS NULL OR c.ends_at > NOW()) OR uc.user_uuid IS NOT NULL`
+	if status != "" {
+		args = append(args, status)
+		filter = `uc.status = $2`
+	}
+
+	rows, err := db.Conn.Query(ctx,
+		`SELECT `+challengeColumns+`, uc.status, uc.progress, uc.enrolled_at, uc.completed_at
+		 FROM challenges c
+		 LEFT JOIN user_challenges uc ON uc.challenge_id = c.id AND uc.user_uuid = $1
+		 WHERE `+filter+`
+		 ORDER BY c.ends_at ASC NULLS LAST, c.id`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []UserChallenge{}
+	for rows.Next() {
+		uc, err := scanUserChallenge(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, uc)
+	}
+	return list, rows.Err()
+}
+
+func scanUserChallenge(row pgx.Row) (UserChallenge, error) {
+	var uc UserChallenge
+	var status *string
+	var progress *int
+	var enrolledAt *time.Time
+	var completedAt *time.Time
+	dest := append(challengeDest(&uc.Challenge), &status, &progress, &enrolledAt, &completedAt)
+	if err := row.Scan(dest...); err != nil {
+		return UserChallenge{}, err
+	}
+	if status != nil {
+		uc.Enrollment = &Enrollment{Status: *status, Progress: *progress, EnrolledAt: *enrolledAt, CompletedAt: completedAt}
+	}
+	return uc, nil
+}
+
+// GetChallenge returns one challenge with the user's enrollment, or
+// ErrChallengeNotFound.
+func GetChallenge(ctx context.Context, userUUID string, id int64) (UserChallenge, error) {
+	if err := expire(ctx, userUUID); err != nil {
+		return UserChallenge{}, err
+	}
+
+	uc, err := scanUserChallenge(db.Conn.QueryRow(ctx,
+		`SELECT `+challengeColumns+`, uc.status, uc.progress, uc.enrolled_at, uc.completed_at
+		 FROM challenges c
+		 LEFT JOIN user_challenges uc ON uc.challenge_id = c.id AND uc.user_uuid = $1
+		 WHERE c.id = $2`,
+		userUUID, id,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return UserChallenge{}, ErrChallengeNotFound
+	}
+	return uc, err
+}
+
+// Enroll joins the user to a challenge that hasn't ended. Visits already
+// made inside the challenge window count straight away. Joining twice is a
+// no-op.
+func Enroll(ctx context.Context, userUUID string, id int64) (UserChallenge, error) {
+	uc, err := GetChallenge(ctx, userUUID, id)
+	if err != nil || uc.Enrollment != nil {
+		return uc, err
+	}
+	if uc.Ended(time.Now()) {
+		return UserChallenge{}, ErrChallengeEnded
+	}
+
+	_, err = db.Conn.Exec(ctx,
+		`INSERT INTO user_challenges (user_uuid, challenge_id, status)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (user_uuid, challenge_id) DO NOTHING`,
+		userUUID, id, StatusPending,
+	)
+	if err != nil {
+		return UserChallenge{}, err
+	}
+
+	if _, err := update(ctx, userUUID, uc.Challenge, StatusPending, 0); err != nil {
+		return UserChallenge{}, err
+	}
+	return GetChallenge(ctx, userUUID, id)
+}
+
+// progress counts the distinct places the user visited inside the
+// challenge window that the challenge targets.
+func progress(ctx context.Context, userUUID string, c Challenge) (int, error) {
+	var n int
+	err := db.Conn.QueryRow(ctx,
+		`SELECT COUNT(DISTINCT v.place_id)
+		 FROM user_visit_history v LEFT JOIN places p ON p.place_id = v.place_id
+		 WHERE v.user_uuid = $1
+		   AND v.visited_at >= $2 AND ($3::timestamptz IS NULL OR v.visited_at < $3)
+		   AND (NOT $4 OR v.verified)
+		   AND ((cardinality($5::text[]) = 0 AND cardinality($6::text[]) = 0)
+			OR v.place_id = ANY($5::text[])
+			OR EXISTS (SELECT 1 FROM unnest(p.categories) cat, unnest($6::text[]) want
+				WHERE cat = want OR cat LIKE want || '.%'))`,
+		userUUID, c.StartsAt, c.EndsAt, c.VerifiedOnly, c.PlaceIDs, c.Categories,
+	).Scan(&n)
+	return n, err
+}
+
+func statusFor(c Challenge, progress int, now time.Time) string {
+	switch {
+	case progress >= c.Goal:
+		return StatusCompleted
+	case c.Ended(now):
+		return StatusExpired
+	case progress > 0:
+		return StatusInProgress
+	default:
+		return StatusPending
+	}
+}
+
+// update recomputes an open enrollment's progress and status, reporting
+// whether it just completed.
+func update(ctx context.Context, userUUID string, c Challenge, status string, prev int) (bool, error) {
+	n, err := progress(ctx, userUUID, c)
+	if err != nil {
+		return false, err
+	}
+	n = min(n, c.Goal)
+	next := statusFor(c, n, time.Now())
+	if next == status && n == prev {
+		return false, nil
+	}
+
+	_, err = db.Conn.Exec(ctx,
+		`UPDATE user_challenges
+		 SET progress=$3, status=$4, updated_at=NOW(),
+			completed_at = CASE WHEN $4 = 'completed' THEN NOW() ELSE completed_at END
+		 WHERE user_uuid=$1 AND challenge_id=$2`,
+		userUUID, c.ID, n, next,
+	)
+	return err == nil && next == StatusCompleted, err
+}
+
+// onVisit advances the user's open enrollments.
+func onVisit(ctx context.Context, e events.Event) {
+	rows, err := db.Conn.Query(ctx,
+		`SELECT `+challengeColumns+`, uc.status, uc.progress
+		 FROM user_challenges uc JOIN challenges c ON c.id = uc.challenge_id
+		 WHERE uc.user_uuid=$1 AND uc.status IN ($2, $3)`,
+		e.UserUUID, StatusPending, StatusInProgress,
+	)
+	if err != nil {
+		log.Printf("Loading challenges failed: %v", err)
+		return
+	}
+
+	type open struct {
+		challenge Challenge
+		status    string
+		progress  int
+	}
+	var enrollments []open
+	for rows.Next() {
+		var o open
+		dest := append(challengeDest(&o.challenge), &o.status, &o.progress)
+		if err := rows.Scan(dest...); err == nil {
+			enrollments = append(enrollments, o)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Printf("Loading challenges failed: %v", err)
+		return
+	}
+
+	for _, o := range enrollments {
+		// An unverified visit can't move a verified-only count, though it's
+		// still a chance to notice the challenge ran out.
+		if o.challenge.VerifiedOnly && !e.Verified && !o.challenge.Ended(time.Now()) {
+			continue
+		}
+		completed, err := update(ctx, e.UserUUID, o.challenge, o.status, o.progress)
+		if err != nil {
+			log.Printf("Challenge %d progress failed: %v", o.challenge.ID, err)
+			continue
+		}
+		if completed {
+			events.Publish(ctx, events.Event{
+				Kind:     events.ChallengeCompleted,
+				UserUUID: e.UserUUID,
+				Ref:      strconv.FormatInt(o.challenge.ID, 10),
+			})
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package challenges
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatusFor(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	tests := []struct {
+		name     string
+		endsAt   *time.Time
+		progress int
+		want     string
+	}{
+		{"nothing yet", nil, 0, StatusPending},
+		{"started", nil, 1, StatusInProgress},
+		{"one to go", at(time.Hour), 2, StatusInProgress},
+		{"reached the goal", at(time.Hour), 3, StatusCompleted},
+		// A goal reached before the end still counts once the end has passed.
+		{"reached, since ended", at(-time.Hour), 3, StatusCompleted},
+		{"ended short", at(-time.Hour), 2, StatusExpired},
+		{"ended untouched", at(-time.Hour), 0, StatusExpired},
+		{"ends right now", at(0), 1, StatusExpired},
+		{"ends in a moment", at(time.Nanosecond), 1, StatusInProgress},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Challenge{Goal: 3, StartsAt: now.Add(-24 * time.Hour), EndsAt: tt.endsAt}
+			if got := statusFor(c, tt.progress, now); got != tt.want {
+				t.Errorf("statusFor(progress %d) = %q, want %q", tt.progress, got, tt.want)
+			}
+		})
+	}
+}
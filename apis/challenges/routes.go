@@ -0,0 +1,21 @@
+package challenges
+
+import (
+	"net/http"
+
+	"example.com/m/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// AdminRoutes serves challenge management, mounted under /admin/challenges.
+func AdminRoutes() http.Handler {
+	r := chi.NewRouter()
+
+	r.Group(func(admin chi.Router) {
+		admin.Use(middleware.Auth, middleware.Admin)
+
+		admin.Post("/", CreateHandler)
+	})
+
+	return r
+}
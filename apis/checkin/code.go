@@ -0,0 +1,104 @@
+package checkin
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// codeVersion prefixes every code so the format can change later without
+// misreading old prints.
+const codeVersion = "v1"
+
+var (
+	ErrNotConfigured = errors.New("QR_CHECKIN_SECRET is not set")
+	ErrInvalidCode   = errors.New("invalid check-in code")
+	ErrExpiredCode   = errors.New("check-in code has expired")
+)
+
+// rotation is how long each code is valid for, from QR_ROTATION_SECONDS
+// (default 10 minutes). A code from the previous period is still accepted,
+// so a scan just as the code changes doesn't fail.
+func rotation() time.Duration {
+	if n, err := strconv.Atoi(os.Getenv("QR_ROTATION_SECONDS")); err == nil && n >= 30 {
+		return time.Duration(n) * time.Second
+	}
+	return 10 * time.Minute
+}
+
+func secret() ([]byte, error) {
+	s := os.Getenv("QR_CHECKIN_SECRET")
+	if s == "" {
+		return nil, ErrNotConfigured
+	}
+	return []byte(s), nil
+}
+
+func period(t time.Time, every time.Duration) int64 {
+	return t.Unix() / int64(every/time.Second)
+}
+
+func sign(key []byte, placeID string, p int64) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(codeVersion + "|" + placeID + "|" + strconv.FormatInt(p, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// IssueCode returns the check-in code for placeID that is current at now,
+// and when it stops being shown.
+func IssueCode(placeID string, now time.Time) (string, time.Time, error) {
+	key, err := secret()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	every := rotation()
+	p := period(now, every)
+	code := strings.Join([]string{
+		codeVersion,
+		base64.RawURLEncoding.EncodeToString([]byte(placeID)),
+		strconv.FormatInt(p, 36),
+		sign(key, placeID, p),
+	}, ".")
+	return code, time.Unix((p+1)*int64(every/time.Second), 0), nil
+}
+
+// ParseCode checks a scanned code's signature and time window and returns
+// the place it was issued for.
+func ParseCode(code string, now time.Time) (string, error) {
+	key, err := secret()
+	if err != nil {
+		return "", err
+	}
+
+	parts := strings.Split(strings.TrimSpace(code), ".")
+	if len(parts) != 4 || parts[0] != codeVersion {
+		return "", ErrInvalidCode
+	}
+	rawID, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || len(rawID) == 0 {
+		return "", ErrInvalidCode
+	}
+	p, err := strconv.ParseInt(parts[2], 36, 64)
+	if err != nil {
+		return "", ErrInvalidCode
+	}
+	placeID := string(rawID)
+	if !hmac.Equal([]byte(sign(key, placeID, p)), []byte(parts[3])) {
+		return "", ErrInvalidCode
+	}
+
+	current := period(now, rotation())
+	if p > current {
+		return "", ErrInvalidCode
+	}
+	if p < current-1 {
+		return "", ErrExpiredCode
+	}
+	return placeID, nil
+}
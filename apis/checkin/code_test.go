@@ -0,0 +1,126 @@
+package checkin
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCodeWindow(t *testing.T) {
+	t.Setenv("QR_CHECKIN_SECRET", "test-secret")
+	t.Setenv("QR_ROTATION_SECONDS", "60")
+
+	// start is the first second of a period.
+	start := time.Unix(60*29_000_000, 0)
+	code, expires, err := IssueCode("konark-sun-temple", start.Add(20*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expires.Equal(start.Add(time.Minute)) {
+		t.Errorf("IssueCode expires at %v, want %v", expires, start.Add(time.Minute))
+	}
+
+	tests := []struct {
+		name    string
+		offset  time.Duration // from start
+		wantErr error
+	}{
+		{"start of its period", 0, nil},
+		{"end of its period", 59 * time.Second, nil},
+		{"start of the next period", 60 * time.Second, nil},
+		{"end of the next period", 119 * time.Second, nil},
+		{"two periods on", 120 * time.Second, ErrExpiredCode},
+		{"long after", 24 * time.Hour, ErrExpiredCode},
+		{"before it was issued", -time.Second, ErrInvalidCode},
+		{"a period early", -60 * time.Second, ErrInvalidCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			placeID, err := ParseCode(code, start.Add(tt.offset))
+			if err != tt.wantErr {
+				t.Fatalf("ParseCode at %v = %v, want %v", tt.offset, err, tt.wantErr)
+			}
+			if err == nil && placeID != "konark-sun-temple" {
+				t.Errorf("ParseCode = %q, want konark-sun-temple", placeID)
+			}
+		})
+	}
+}
+
+func TestParseCodeTampered(t *testing.T) {
+	t.Setenv("QR_CHECKIN_SECRET", "test-secret")
+	t.Setenv("QR_ROTATION_SECONDS", "60")
+
+	now := time.Unix(60*29_000_000+30, 0)
+	code, _, err := IssueCode("konark-sun-temple", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(code, ".")
+	with := func(i int, v string) string {
+		p := append([]string(nil), parts...)
+		p[i] = v
+		return strings.Join(p, ".")
+	}
+	otherID := base64.RawURLEncoding.EncodeToString([]byte("puri-jagannath"))
+
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+	}{
+		{"untouched", code, nil},
+		{"surrounding whitespace", "  " + code + "\n", nil},
+		{"other place", with(1, otherID), ErrInvalidCode},
+		{"other period", with(2, "0"), ErrInvalidCode},
+		{"bad period", with(2, "!!"), ErrInvalidCode},
+		{"bad signature", with(3, "AAAAAAAAAAAAAAAAAAAAAA"), ErrInvalidCode},
+		{"empty place", with(1, ""), ErrInvalidCode},
+		{"place not base64", with(1, "%%"), ErrInvalidCode},
+		{"other version", with(0, "v2"), ErrInvalidCode},
+		{"missing part", strings.Join(parts[:3], "."), ErrInvalidCode},
+		{"extra part", code + ".x", ErrInvalidCode},
+		{"empty", "", ErrInvalidCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCode(tt.code, now); err != tt.wantErr {
+				t.Errorf("ParseCode(%q) = %v, want %v", tt.code, err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("other secret", func(t *testing.T) {
+		t.Setenv("QR_CHECKIN_SECRET", "another-secret")
+		if _, err := ParseCode(code, now); err != ErrInvalidCode {
+			t.Errorf("ParseCode with another secret = %v, want ErrInvalidCode", err)
+		}
+	})
+	t.Run("no secret", func(t *testing.T) {
+		t.Setenv("QR_CHECKIN_SECRET", "")
+		if _, err := ParseCode(code, now); err != ErrNotConfigured {
+			t.Errorf("ParseCode without a secret = %v, want ErrNotConfigured", err)
+		}
+	})
+}
+
+func TestRotation(t *testing.T) {
+	tests := []struct {
+		env  string
+		want time.Duration
+	}{
+		{"", 10 * time.Minute},
+		{"300", 5 * time.Minute},
+		{"30", 30 * time.Second},
+		{"29", 10 * time.Minute},
+		{"-60", 10 * time.Minute},
+		{"soon", 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Setenv("QR_ROTATION_SECONDS", tt.env)
+		if got := rotation(); got != tt.want {
+			t.Errorf("rotation() with %q = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package checkin
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"example.com/m/apis/findplaces"
+	"example.com/m/apis/useractions"
+	"example.com/m/db"
+	"example.com/m/i18n"
+	"example.com/m/utils"
+	"github.com/go-chi/chi/v5"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// checkinCooldown stops one user checking in to the same place repeatedly
+// by rescanning as the code rotates.
+const checkinCooldown = time.Hour
+
+type CodeResponse struct {
+	PlaceID   string    `json:"place_id"`
+	Code      string    `json:"code"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type CheckinRequest struct {
+	Code string `json:"code"`
+}
+
+type CheckinResponse struct {
+	Status  string `json:"status" example:"checked_in"`
+	PlaceID string `json:"place_id"`
+	useractions.VisitCheck
+}
+
+// QRCodeHandler renders the current check-in code for a place
+// @Summary Place Check-in QR Code
+// @Description The place's current signed check-in code as a PNG or SVG QR code, or as JSON for screens that draw their own. Codes rotate every QR_ROTATION_SECONDS (default 600); X-Code-Expires-At says when to refresh. Admin only.
+// @Tags admin
+// @Produce image/png
+// @Produce image/svg+xml
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Place ID"
+// @Param format query string false "png (default), svg or json"
+// @Param size query int false "PNG size in pixels (default 512, 128-2048)"
+// @Success 200 {object} CodeResponse
+// @Failure 400 {object} map[string]string "Invalid format"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 404 {object} map[string]string "Place not found"
+// @Failure 503 {object} map[string]string "QR check-in not configured"
+// @Router /admin/places/{id}/qr [get]
+func QRCodeHandler(w http.ResponseWriter, r *http.Request) {
+	placeID := chi.URLParam(r, "id")
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "png"
+	}
+	if format != "png" && format != "svg" && format != "json" {
+		i18n.Error(w, r, i18n.InvalidQRFormat, http.StatusBadRequest)
+		return
+	}
+	size, err := strconv.Atoi(r.URL.Query().Get("size"))
+	if err != nil || size < 128 || size > 2048 {
+		size = 512
+	}
+
+	if _, err := findplaces.LookupPlace(r.Context(), db.Conn, findplaces.DefaultProvider(), placeID); err != nil {
+		if errors.Is(err, findplaces.ErrPlaceNotFound) {
+			i18n.Error(w, r, i18n.PlaceNotFound, http.StatusNotFound)
+			return
+		}
+		log.Printf("QR place lookup failed: %v", err)
+		i18n.Error(w, r, i18n.FetchPlaceFailed, http.StatusBadGateway)
+		return
+	}
+
+	code, expires, err := IssueCode(placeID, time.Now())
+	if err != nil {
+		log.Printf("QR code issue failed: %v", err)
+		i18n.Error(w, r, i18n.QRNotConfigured, http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Code-Expires-At", expires.UTC().Format(time.RFC3339))
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(CodeResponse{PlaceID: placeID, Code: code, ExpiresAt: expires.UTC()})
+		return
+	}
+
+	q, err := qrcode.New(code, qrcode.Medium)
+	if err != nil {
+		log.Printf("QR encode failed: %v", err)
+		i18n.Error(w, r, i18n.QRFailed, http.StatusInternalServerError)
+		return
+	}
+
+	if format == "svg" {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write(renderSVG(q))
+		return
+	}
+
+	png, err := q.PNG(size)
+	if err != nil {
+		log.Printf("QR render failed: %v", err)
+		i18n.Error(w, r, i18n.QRFailed, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(png)
+}
+
+// QRCheckinHandler checks in with a scanned QR code
+// @Summary QR Check-in
+// @Description Check in at a place by scanning its on-site QR code. A valid, unexpired code records a verified visit; checking in to the same place again within an hour is rejected.
+// @Tags useractions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CheckinRequest true "Scanned code"
+// @Success 200 {object} CheckinResponse
+// @Failure 400 {object} map[string]string "Invalid or expired code"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 409 {object} map[string]string "Already checked in"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 503 {object} map[string]string "QR check-in not configured"
+// @Router /api/user/checkin/qr [post]
+func QRCheckinHandler(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userUUID, err := utils.GetUserUUIDFromCtx(ctx)
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	var req CheckinRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		i18n.Error(w, r, i18n.InvalidQRCode, http.StatusBadRequest)
+		return
+	}
+
+	placeID, err := ParseCode(req.Code, time.Now())
+	switch {
+	case errors.Is(err, ErrNotConfigured):
+		i18n.Error(w, r, i18n.QRNotConfigured, http.StatusServiceUnavailable)
+		return
+	case errors.Is(err, ErrExpiredCode):
+		i18n.Error(w, r, i18n.ExpiredQRCode, http.StatusBadRequest)
+		return
+	case err != nil:
+		i18n.Error(w, r, i18n.InvalidQRCode, http.StatusBadRequest)
+		return
+	}
+
+	check := useractions.VisitCheck{Verified: true, Method: useractions.MethodQR}
+	err = useractions.LogVisitOnce(ctx, userUUID, placeID, nil, check, checkinCooldown)
+	if errors.Is(err, useractions.ErrRecentVisit) {
+		i18n.Error(w, r, i18n.AlreadyCheckedIn, http.StatusConflict)
+		return
+	}
+	if err != nil {
+		log.Printf("Check-in save failed: %v", err)
+		i18n.Error(w, r, i18n.CheckinFailed, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(CheckinResponse{Status: "checked_in", PlaceID: placeID, VisitCheck: check})
+}
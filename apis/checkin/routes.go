@@ -0,0 +1,22 @@
+package checkin
+
+import (
+	"net/http"
+
+	"example.com/m/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+// AdminRoutes serves the admin-only check-in endpoints, mounted under
+// /admin/places.
+func AdminRoutes() http.Handler {
+	r := chi.NewRouter()
+
+	r.Group(func(admin chi.Router) {
+		admin.Use(middleware.Auth, middleware.Admin)
+
+		admin.Get("/{id}/qr", QRCodeHandler)
+	})
+
+	return r
+}
@@ -0,0 +1,60 @@
+package findplaces
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"example.com/m/i18n"
+)
+
+type UsageResponse struct {
+	Provider    string       `json:"provider"`
+	Today       int          `json:"today"`
+	DailyBudget int          `json:"daily_budget"`
+	Exhausted   bool         `json:"exhausted"`
+	History     []DailyUsage `json:"history"`
+}
+
+// UsageHandler reports upstream API usage
+// @Summary Upstream API Usage
+// @Description Daily Geoapify call counts and the configured budget (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Days of history (default 30, max 365)"
+// @Success 200 {object} UsageResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Admin access required"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /admin/usage [get]
+func UsageHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		i18n.JSONError(w, r, i18n.MethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+
+	days, err := strconv.Atoi(r.URL.Query().Get("days"))
+	if err != nil || days <= 0 || days > 365 {
+		days = 30
+	}
+
+	history, err := GetUsage(r.Context(), days)
+	if err != nil {
+		log.Printf("Usage query failed: %v", err)
+		i18n.JSONError(w, r, i18n.UsageFailed, http.StatusInternalServerError)
+		return
+	}
+
+	usage := DefaultClient().Usage
+	json.NewEncoder(w).Encode(UsageResponse{
+		Provider:    usage.Provider,
+		Today:       usage.Today(),
+		DailyBudget: usage.DailyBudget,
+		Exhausted:   usage.Exhausted(),
+		History:     history,
+	})
+}
@@ -0,0 +1,294 @@
+package findplaces
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// BBox is a GeoJSON-style bounding box: [west, south, east, north]. A box
+// whose west edge is greater than its east edge crosses the antimeridian.
+type BBox [4]float64
+
+func (b BBox) West() float64  { return b[0] }
+func (b BBox) South() float64 { return b[1] }
+func (b BBox) East() float64  { return b[2] }
+func (b BBox) North() float64 { return b[3] }
+
+func (b BBox) CrossesAntimeridian() bool { return b.West() > b.East() }
+
+func (b BBox) Validate() error {
+	if !validCoordinate(b.South(), b.West()) || !validCoordinate(b.North(), b.East()) {
+		return errors.New("bbox out of range")
+	}
+	if b.South() >= b.North() {
+		return errors.New("bbox south must be below north")
+	}
+	if b.West() == b.East() {
+		return errors.New("bbox has no width")
+	}
+	return nil
+}
+
+// Split returns the box as one or two boxes that don't cross the
+// antimeridian.
+func (b BBox) Split() []BBox {
+	if !b.CrossesAntimeridian() {
+		return []BBox{b}
+	}
+	return []BBox{
+		{b.West(), b.South(), 180, b.North()},
+		{-180, b.South(), b.East(), b.North()},
+	}
+}
+
+func (b BBox) Contains(lat, lon float64) bool {
+	if lat < b.South() || lat > b.North() {
+		return false
+	}
+	if b.CrossesAntimeridian() {
+		return lon >= b.West() || lon <= b.East()
+	}
+	return lon >= b.West() && lon <= b.East()
+}
+
+func (b BBox) Center() Point {
+	east := b.East()
+	if b.CrossesAntimeridian() {
+		east += 360
+	}
+	return Point{Lat: (b.South() + b.North()) / 2, Lon: normalizeLon((b.West() + east) / 2)}
+}
+
+// Polygon is a GeoJSON Polygon geometry: an outer ring followed by optional
+// holes, each a closed ring of [lon, lat] positions.
+type Polygon struct {
+	Type        string         `json:"type" example:"Polygon"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+func (p Polygon) Validate() error {
+	if p.Type != "Polygon" {
+		return fmt.Errorf("unsupported geometry type %q", p.Type)
+	}
+	if len(p.Coordinates) == 0 {
+		return errors.New("polygon has no rings")
+	}
+	for _, ring := range p.Coordinates {
+		if len(ring) < 4 {
+			return errors.New("polygon ring needs at least 4 positions")
+		}
+		if ring[0] != ring[len(ring)-1] {
+			return errors.New("polygon ring is not closed")
+		}
+		for _, pos := range ring {
+			if !validCoordinate(pos[1], pos[0]) {
+				return errors.New("polygon position out of range")
+			}
+		}
+	}
+	return nil
+}
+
+// crossesAntimeridian guesses whether the outer ring wraps around 180°: any
+// edge spanning more than half the globe is taken to go the short way round.
+func (p Polygon) crossesAntimeridian() bool {
+	ring := p.Coordinates[0]
+	for i := 1; i < len(ring); i++ {
+		if math.Abs(ring[i][0]-ring[i-1][0]) > 180 {
+			return true
+		}
+	}
+	return false
+}
+
+// unwrap shifts western-hemisphere longitudes by 360° for polygons that
+// cross the antimeridian, so the ring is continuous.
+func unwrap(lon float64, crosses bool) float64 {
+	if crosses && lon < 0 {
+		return lon + 360
+	}
+	return lon
+}
+
+func (p Polygon) Bounds() BBox {
+	crosses := p.crossesAntimeridian()
+	b := BBox{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+	for _, pos := range p.Coordinates[0] {
+		lon := unwrap(pos[0], crosses)
+		b[0] = math.Min(b[0], lon)
+		b[1] = math.Min(b[1], pos[1])
+		b[2] = math.Max(b[2], lon)
+		b[3] = math.Max(b[3], pos[1])
+	}
+	b[0], b[2] = normalizeLon(b[0]), normalizeLon(b[2])
+	return b
+}
+
+// Contains reports whether a point lies inside the outer ring and outside
+// every hole.
+func (p Polygon) Contains(lat, lon float64) bool {
+	crosses := p.crossesAntimeridian()
+	lon = unwrap(lon, crosses)
+
+	if !ringContains(p.Coordinates[0], lat, lon, crosses) {
+		return false
+	}
+	for _, hole := range p.Coordinates[1:] {
+		if ringContains(hole, lat, lon, crosses) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains is the even-odd ray casting test, treating lon/lat as planar.
+func ringContains(ring [][2]float64, lat, lon float64, crosses bool) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := unwrap(ring[i][0], crosses), ring[i][1]
+		xj, yj := unwrap(ring[j][0], crosses), ring[j][1]
+		if (yi > lat) != (yj > lat) && lon < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func normalizeLon(lon float64) float64 {
+	for lon > 180 {
+		lon -= 360
+	}
+	for lon < -180 {
+		lon += 360
+	}
+	return lon
+}
+
+// searchArea is the region a FindPlacesRequest asks about: a circle, a
+// bounding box or a polygon (searched via its bounds, then filtered).
+type searchArea struct {
+	center  Point
+	radius  int // the circle's radius, or the area's circumscribed radius
+	box     *BBox
+	polygon *Polygon
+}
+
+func (req FindPlacesRequest) searchArea() (searchArea, error) {
+	switch {
+	case req.Polygon != nil && req.BBox != nil:
+		return searchArea{}, errors.New("send either bbox or polygon, not both")
+
+	case req.Polygon != nil:
+		if err := req.Polygon.Validate(); err != nil {
+			return searchArea{}, err
+		}
+		box := req.Polygon.Bounds()
+		return searchArea{center: box.Center(), radius: circumRadius(box), box: &box, polygon: req.Polygon}, nil
+
+	case req.BBox != nil:
+		if err := req.BBox.Validate(); err != nil {
+			return searchArea{}, err
+		}
+		box := *req.BBox
+		return searchArea{center: box.Center(), radius: circumRadius(box), box: &box}, nil
+	}
+
+	if req.Latitude == 0 || req.Longitude == 0 {
+		return searchArea{}, errors.New("Latitude and Longitude required")
+	}
+	return searchArea{center: Point{Lat: req.Latitude, Lon: req.Longitude}, radius: req.RadiusMeters}, nil
+}
+
+func circumRadius(b BBox) int {
+	c := b.Center()
+	return int(math.Ceil(math.Max(
+		Haversine(c.Lat, c.Lon, b.South(), b.West()),
+		Haversine(c.Lat, c.Lon, b.North(), b.East()),
+	)))
+}
+
+func (a searchArea) isCircle() bool { return a.box == nil }
+
+func (a searchArea) kind() string {
+	switch {
+	case a.polygon != nil:
+		return "polygon"
+	case a.box != nil:
+		return "bbox"
+	}
+	return "circle"
+}
+
+func (a searchArea) contains(lat, lon float64) bool {
+	switch {
+	case a.polygon != nil:
+		return a.polygon.Contains(lat, lon)
+	case a.box != nil:
+		return a.box.Contains(lat, lon)
+	}
+	return Haversine(a.center.Lat, a.center.Lon, lat, lon) <= float64(a.radius)
+}
+
+func (a searchArea) cacheKey(category string) string {
+	switch {
+	case a.polygon != nil:
+		var sb strings.Builder
+		for _, ring := range a.polygon.Coordinates {
+			for _, pos := range ring {
+				fmt.Fprintf(&sb, "%.5f,%.5f;", pos[0], pos[1])
+			}
+			sb.WriteByte('|')
+		}
+		sum := sha1.Sum([]byte(sb.String()))
+		return fmt.Sprintf("poly:%s:%s", hex.EncodeToString(sum[:10]), category)
+	case a.box != nil:
+		return fmt.Sprintf("bbox:%.4f:%.4f:%.4f:%.4f:%s", a.box.West(), a.box.South(), a.box.East(), a.box.North(), category)
+	}
+	return BuildCacheKey(a.center.Lat, a.center.Lon, a.radius, category)
+}
+
+// fetch asks the provider for places in the area. Boxes crossing the
+// antimeridian are fetched as two halves; polygons are fetched by their
+// bounds and then clipped.
+func (a searchArea) fetch(ctx context.Context, provider Provider, limit int, category string) ([]Place, error) {
+	if a.isCircle() {
+		return provider.FetchPlaces(ctx, a.center.Lat, a.center.Lon, a.radius, limit, category)
+	}
+
+	upstreamLimit := limit
+	if a.polygon != nil {
+		// Some of what's in the bounds will fall outside the polygon.
+		upstreamLimit = min(limit*3, 500)
+	}
+
+	var places []Place
+	boxes := a.box.Split()
+	for _, box := range boxes {
+		part, err := provider.FetchPlacesInBox(ctx, box, upstreamLimit, category)
+		if err != nil {
+			return nil, err
+		}
+		places = append(places, part...)
+	}
+
+	kept := make([]Place, 0, len(places))
+	for _, p := range places {
+		if a.polygon != nil && !a.polygon.Contains(p.Lat, p.Lon) {
+			continue
+		}
+		p.DistanceMeters = Haversine(a.center.Lat, a.center.Lon, p.Lat, p.Lon)
+		kept = append(kept, p)
+	}
+	if len(boxes) > 1 {
+		// Each half may fill the limit on its own; keep the places nearest
+		// the middle rather than whatever the western half returned.
+		sort.SliceStable(kept, func(i, j int) bool { return kept[i].DistanceMeters < kept[j].DistanceMeters })
+	}
+	return mergePlaces(nil, kept, limit), nil
+}
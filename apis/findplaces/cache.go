@@ -4,42 +4,105 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// cacheTTL is how long a cached response is served without asking Geoapify
+// again. Expired entries are kept around as a fallback for when upstream is
+// unavailable.
+const cacheTTL = 24 * time.Hour
+
+// cacheReuseDistance is how far (meters) from a cached circle search a new
+// one may be and still be served from it.
+const cacheReuseDistance = 100.0
+
+// reverseCacheTTL is the equivalent for reverse geocoding; what town a spot
+// is in changes far less often than what's around it.
+const reverseCacheTTL = 30 * 24 * time.Hour
+
+// reverseGeohashPrecision sets the reverse geocoding cache cell to ~150m.
+const reverseGeohashPrecision = 7
+
+type CachedEntry struct {
+	Response  FindPlacesResponse
+	Lat       float64
+	Lon       float64
+	CreatedAt time.Time
+}
+
+func (e CachedEntry) Expired() bool {
+	return time.Since(e.CreatedAt) > cacheTTL
+}
+
 func BuildCacheKey(lat, lon float64, radius int, category string) string {
 	return fmt.Sprintf("%.4f:%.4f:%d:%s", lat, lon, radius, category)
 }
 
-func GetCachedResponse(ctx context.Context, conn *pgx.Conn, key string) (FindPlacesResponse, float64, float64, bool) {
+func GetCachedResponse(ctx context.Context, conn *pgxpool.Pool, key string) (CachedEntry, bool) {
 	var raw []byte
-	var lat, lon float64
+	var entry CachedEntry
 	err := conn.QueryRow(ctx,
-		`SELECT response, latitude, longitude FROM poi_cache WHERE cache_key=$1`,
+		`SELECT response, latitude, longitude, created_at FROM poi_cache WHERE cache_key=$1`,
 		key,
-	).Scan(&raw, &lat, &lon)
+	).Scan(&raw, &entry.Lat, &entry.Lon, &entry.CreatedAt)
 
 	if err != nil {
-		return FindPlacesResponse{}, 0, 0, false
+		return CachedEntry{}, false
 	}
 
-	var res FindPlacesResponse
-	_ = json.Unmarshal(raw, &res)
-	return res, lat, lon, true
+	if err := json.Unmarshal(raw, &entry.Response); err != nil {
+		return CachedEntry{}, false
+	}
+	return entry, true
 }
 
-func CacheResponse(ctx context.Context, conn *pgx.Conn, key string,
-	lat, lon float64, radius int, category string, res FindPlacesResponse) error {
+// CacheResponse stores a search result. kind is the searchArea kind
+// ("circle", "bbox" or "polygon"); only circles are reused by proximity.
+func CacheResponse(ctx context.Context, conn *pgxpool.Pool, key string,
+	lat, lon float64, radius int, category, kind string, res FindPlacesResponse) error {
 
 	raw, _ := json.Marshal(res)
 
 	_, err := conn.Exec(ctx,
-		`INSERT INTO poi_cache(cache_key,latitude,longitude,radius,category,response)
-		VALUES($1,$2,$3,$4,$5,$6)
+		`INSERT INTO poi_cache(cache_key,latitude,longitude,radius,category,area_kind,response)
+		VALUES($1,$2,$3,$4,$5,$6,$7)
 		ON CONFLICT(cache_key) DO UPDATE
-		SET response=EXCLUDED.response`,
-		key, lat, lon, radius, category, raw,
+		SET response=EXCLUDED.response, created_at=NOW()`,
+		key, lat, lon, radius, category, kind, raw,
+	)
+	return err
+}
+
+// GetCachedReverse returns the cached reverse geocoding result for a geohash
+// cell and whether it has expired.
+func GetCachedReverse(ctx context.Context, conn *pgxpool.Pool, geohash string) (Place, bool, bool) {
+	var raw []byte
+	var createdAt time.Time
+	err := conn.QueryRow(ctx,
+		`SELECT response, created_at FROM reverse_geocode_cache WHERE geohash=$1`,
+		geohash,
+	).Scan(&raw, &createdAt)
+	if err != nil {
+		return Place{}, false, false
+	}
+
+	var p Place
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return Place{}, false, false
+	}
+	return p, true, time.Since(createdAt) > reverseCacheTTL
+}
+
+func CacheReverse(ctx context.Context, conn *pgxpool.Pool, geohash string, p Place) error {
+	raw, _ := json.Marshal(p)
+
+	_, err := conn.Exec(ctx,
+		`INSERT INTO reverse_geocode_cache(geohash, response) VALUES($1, $2)
+		ON CONFLICT(geohash) DO UPDATE
+		SET response=EXCLUDED.response, created_at=NOW()`,
+		geohash, raw,
 	)
 	return err
 }
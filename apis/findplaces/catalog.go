@@ -0,0 +1,104 @@
+package findplaces
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// placeColumns is the column list scanPlace expects, in order.
+const placeColumns = `place_id, name, latitude, longitude, address_line1, address_line2,
+	formatted, street, city, state, country, postcode, categories, names, rating_avg, rating_count, images`
+
+// placeDest returns scan targets for placeColumns, in order.
+func placeDest(p *Place) []any {
+	return []any{
+		&p.PlaceID, &p.Name, &p.Lat, &p.Lon, &p.AddressLine1, &p.AddressLine2,
+		&p.Formatted, &p.Street, &p.City, &p.State, &p.Country, &p.Postcode, &p.Categories, &p.Names,
+		&p.Rating, &p.RatingCount, &p.Images,
+	}
+}
+
+func scanPlace(row pgx.Row) (Place, error) {
+	var p Place
+	err := row.Scan(placeDest(&p)...)
+	return p, err
+}
+
+// UpsertPlaces records places in the catalog, refreshing rows we've already
+// seen. Distance is request-specific and deliberately not stored.
+func UpsertPlaces(ctx context.Context, conn *pgxpool.Pool, provider string, places []Place) error {
+	if len(places) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, p := range places {
+		categories := p.Categories
+		if categories == nil {
+			categories = []string{}
+		}
+		names := p.Names
+		if names == nil {
+			names = map[string]string{}
+		}
+		images := p.Images
+		if images == nil {
+			images = []Image{}
+		}
+		batch.Queue(
+			`INSERT INTO places(place_id, provider, name, latitude, longitude, address_line1,
+				address_line2, formatted, street, city, state, country, postcode, categories, names, images)
+			VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16)
+			ON CONFLICT(place_id) DO UPDATE SET
+				provider=EXCLUDED.provider, name=EXCLUDED.name,
+				latitude=EXCLUDED.latitude, longitude=EXCLUDED.longitude,
+				address_line1=EXCLUDED.address_line1, address_line2=EXCLUDED.address_line2,
+				formatted=EXCLUDED.formatted, street=EXCLUDED.street, city=EXCLUDED.city,
+				state=EXCLUDED.state, country=EXCLUDED.country, postcode=EXCLUDED.postcode,
+				categories=EXCLUDED.categories,
+				names=places.names || EXCLUDED.names,
+				images=CASE WHEN jsonb_array_length(EXCLUDED.images) > 0
+					THEN EXCLUDED.images ELSE places.images END,
+				updated_at=NOW()`,
+			p.PlaceID, provider, p.Name, p.Lat, p.Lon, p.AddressLine1,
+			p.AddressLine2, p.Formatted, p.Street, p.City, p.State, p.Country, p.Postcode, categories, names,
+			images,
+		)
+	}
+
+	return conn.SendBatch(ctx, batch).Close()
+}
+
+// GetPlace returns a place from the catalog, or ErrPlaceNotFound.
+func GetPlace(ctx context.Context, conn *pgxpool.Pool, placeID string) (Place, error) {
+	p, err := scanPlace(conn.QueryRow(ctx,
+		`SELECT `+placeColumns+` FROM places WHERE place_id=$1`, placeID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Place{}, ErrPlaceNotFound
+	}
+	return p, err
+}
+
+// LookupPlace resolves a place from the catalog, asking the provider on a
+// miss and remembering what it returns.
+func LookupPlace(ctx context.Context, conn *pgxpool.Pool, provider Provider, placeID string) (Place, error) {
+	p, err := GetPlace(ctx, conn, placeID)
+	if !errors.Is(err, ErrPlaceNotFound) {
+		return p, err
+	}
+
+	p, err = provider.PlaceDetails(ctx, placeID)
+	if err != nil {
+		return Place{}, err
+	}
+	p.DistanceMeters = 0
+
+	if err := UpsertPlaces(ctx, conn, provider.Name(), []Place{p}); err != nil {
+		log.Printf("Catalog save failed: %v", err)
+	}
+	return p, nil
+}
@@ -0,0 +1,182 @@
+package findplaces
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"example.com/m/geoformat"
+)
+
+const (
+	ClusterGrid   = "grid"
+	ClusterDBSCAN = "dbscan"
+)
+
+// ClusterOptions asks /findplaces to merge places that would overlap on a map
+// at Zoom. RadiusPx is how close (in screen pixels at that zoom) places must
+// be to merge.
+type ClusterOptions struct {
+	Zoom     int    `json:"zoom" example:"12"`
+	Method   string `json:"method,omitempty" enums:"grid,dbscan" default:"grid"`
+	RadiusPx int    `json:"radius_px,omitempty" default:"60"`
+}
+
+func (o *ClusterOptions) Validate() error {
+	if o.Method == "" {
+		o.Method = ClusterGrid
+	}
+	if o.RadiusPx == 0 {
+		o.RadiusPx = 60
+	}
+	if o.Zoom < 0 || o.Zoom > 22 {
+		return errors.New("cluster zoom must be between 0 and 22")
+	}
+	if o.Method != ClusterGrid && o.Method != ClusterDBSCAN {
+		return errors.New("cluster method must be grid or dbscan")
+	}
+	if o.RadiusPx < 1 || o.RadiusPx > 512 {
+		return errors.New("cluster radius_px must be between 1 and 512")
+	}
+	return nil
+}
+
+// Cluster is a group of places that would overlap at the requested zoom.
+// Representative is the first member in result order.
+type Cluster struct {
+	Centroid       Point    `json:"centroid"`
+	Count          int      `json:"count"`
+	BBox           BBox     `json:"bbox" swaggertype:"array,number"`
+	Representative Place    `json:"representative"`
+	PlaceIDs       []string `json:"place_ids"`
+}
+
+// clusterPlaces groups places per opts. Places that end up alone are returned
+// as-is; groups of two or more become clusters. Both keep input order.
+func clusterPlaces(places []Place, opts ClusterOptions) ([]Place, []Cluster) {
+	var groups [][]int
+	if opts.Method == ClusterDBSCAN {
+		groups = dbscanGroups(places, opts)
+	} else {
+		groups = gridGroups(places, opts)
+	}
+
+	singles := make([]Place, 0)
+	clusters := make([]Cluster, 0)
+	for _, g := range groups {
+		if len(g) == 1 {
+			singles = append(singles, places[g[0]])
+			continue
+		}
+		clusters = append(clusters, newCluster(places, g))
+	}
+	return singles, clusters
+}
+
+// worldPixel projects to web mercator pixel coordinates at zoom (256px tiles).
+func worldPixel(lat, lon float64, zoom int) (float64, float64) {
+	size := 256 * math.Exp2(float64(zoom))
+	latR := lat * math.Pi / 180
+	x := (lon + 180) / 360 * size
+	y := (1 - math.Log(math.Tan(latR)+1/math.Cos(latR))/math.Pi) / 2 * size
+	return x, y
+}
+
+// gridGroups buckets places into RadiusPx-sized screen cells.
+func gridGroups(places []Place, opts ClusterOptions) [][]int {
+	cell := float64(opts.RadiusPx)
+	index := map[[2]int]int{}
+	var groups [][]int
+
+	for i, p := range places {
+		x, y := worldPixel(p.Lat, p.Lon, opts.Zoom)
+		k := [2]int{int(math.Floor(x / cell)), int(math.Floor(y / cell))}
+		if g, ok := index[k]; ok {
+			groups[g] = append(groups[g], i)
+			continue
+		}
+		index[k] = len(groups)
+		groups = append(groups, []int{i})
+	}
+	return groups
+}
+
+// dbscanGroups runs DBSCAN with minPts=2 and eps=RadiusPx converted to meters
+// at the zoom level, so chains of close places merge regardless of grid
+// boundaries.
+func dbscanGroups(places []Place, opts ClusterOptions) [][]int {
+	eps := func(lat float64) float64 {
+		metersPerPixel := 156543.03392 * math.Cos(lat*math.Pi/180) / math.Exp2(float64(opts.Zoom))
+		return float64(opts.RadiusPx) * metersPerPixel
+	}
+
+	neighbors := func(i int) []int {
+		var out []int
+		r := eps(places[i].Lat)
+		for j := range places {
+			if j != i && Haversine(places[i].Lat, places[i].Lon, places[j].Lat, places[j].Lon) <= r {
+				out = append(out, j)
+			}
+		}
+		return out
+	}
+
+	assigned := make([]bool, len(places))
+	var groups [][]int
+	for i := range places {
+		if assigned[i] {
+			continue
+		}
+		assigned[i] = true
+		group := []int{i}
+
+		queue := neighbors(i)
+		for len(queue) > 0 {
+			j := queue[0]
+			queue = queue[1:]
+			if assigned[j] {
+				continue
+			}
+			assigned[j] = true
+			group = append(group, j)
+			queue = append(queue, neighbors(j)...)
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func newCluster(places []Place, members []int) Cluster {
+	first := places[members[0]]
+	c := Cluster{
+		Count:          len(members),
+		Representative: first,
+		BBox:           BBox{first.Lon, first.Lat, first.Lon, first.Lat},
+	}
+
+	var sumLat, sumLon float64
+	for _, i := range members {
+		p := places[i]
+		sumLat += p.Lat
+		sumLon += p.Lon
+		c.BBox[0] = math.Min(c.BBox[0], p.Lon)
+		c.BBox[1] = math.Min(c.BBox[1], p.Lat)
+		c.BBox[2] = math.Max(c.BBox[2], p.Lon)
+		c.BBox[3] = math.Max(c.BBox[3], p.Lat)
+		c.PlaceIDs = append(c.PlaceIDs, p.PlaceID)
+	}
+	c.Centroid = Point{Lat: sumLat / float64(len(members)), Lon: sumLon / float64(len(members))}
+	return c
+}
+
+func (c Cluster) Feature() geoformat.Feature {
+	return geoformat.Feature{
+		ID:          "cluster:" + c.Representative.PlaceID,
+		Name:        fmt.Sprintf("%d places", c.Count),
+		Lat:         c.Centroid.Lat,
+		Lon:         c.Centroid.Lon,
+		Description: c.Representative.Name,
+		Categories:  c.Representative.Categories,
+		Properties:  c,
+	}
+}
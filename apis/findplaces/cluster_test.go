@@ -0,0 +1,155 @@
+package findplaces
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func place(id string, lat, lon float64) Place {
+	return Place{PlaceID: id, Name: id, Lat: lat, Lon: lon}
+}
+
+// groupIDs names the members of each group.
+func groupIDs(places []Place, groups [][]int) [][]string {
+	out := make([][]string, len(groups))
+	for i, g := range groups {
+		for _, j := range g {
+			out[i] = append(out[i], places[j].PlaceID)
+		}
+		slices.Sort(out[i])
+	}
+	return out
+}
+
+// gridEdge returns a longitude on a grid cell boundary near lon.
+func gridEdge(lon float64, opts ClusterOptions) float64 {
+	size := 256 * math.Exp2(float64(opts.Zoom))
+	cell := float64(opts.RadiusPx)
+	x, _ := worldPixel(0, lon, opts.Zoom)
+	return math.Round(x/cell)*cell/size*360 - 180
+}
+
+func TestClusterGroups(t *testing.T) {
+	// At zoom 12 and latitude 20, 60px is about 2.15km; 0.0143° of
+	// longitude is about 1.5km.
+	opts := ClusterOptions{Zoom: 12, Method: ClusterDBSCAN, RadiusPx: 60}
+	edge := gridEdge(85.5, opts)
+
+	tests := []struct {
+		name   string
+		places []Place
+		dbscan [][]string
+		grid   [][]string
+	}{
+		{
+			name:   "empty",
+			places: nil,
+		},
+		{
+			name:   "one place",
+			places: []Place{place("a", 20, 85)},
+			dbscan: [][]string{{"a"}},
+			grid:   [][]string{{"a"}},
+		},
+		{
+			name:   "same spot",
+			places: []Place{place("a", 20, 85.001), place("b", 20, 85.001)},
+			dbscan: [][]string{{"a", "b"}},
+			grid:   [][]string{{"a", "b"}},
+		},
+		{
+			name:   "far apart",
+			places: []Place{place("a", 20, 85), place("b", 20, 85.1), place("c", 20.1, 85)},
+			dbscan: [][]string{{"a"}, {"b"}, {"c"}},
+			grid:   [][]string{{"a"}, {"b"}, {"c"}},
+		},
+		{
+			name: "chain merges under dbscan",
+			places: []Place{
+				place("a", 20, edge-0.0143), place("b", 20, edge), place("c", 20, edge+0.0143),
+				place("far", 20, edge+0.1),
+			},
+			dbscan: [][]string{{"a", "b", "c"}, {"far"}},
+		},
+		{
+			name:   "straddling a grid line",
+			places: []Place{place("west", 20, edge-0.001), place("east", 20, edge+0.001)},
+			dbscan: [][]string{{"east", "west"}},
+			grid:   [][]string{{"west"}, {"east"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := groupIDs(tt.places, dbscanGroups(tt.places, opts))
+			if !slices.EqualFunc(got, tt.dbscan, slices.Equal) {
+				t.Errorf("dbscanGroups = %v, want %v", got, tt.dbscan)
+			}
+			if tt.grid == nil && len(tt.places) > 0 {
+				return
+			}
+			got = groupIDs(tt.places, gridGroups(tt.places, opts))
+			if !slices.EqualFunc(got, tt.grid, slices.Equal) {
+				t.Errorf("gridGroups = %v, want %v", got, tt.grid)
+			}
+		})
+	}
+}
+
+func TestClusterPlaces(t *testing.T) {
+	places := []Place{
+		place("a", 20, 85), place("lone", 20.2, 85.2), place("b", 20.001, 85.001), place("c", 19.999, 85.002),
+	}
+	for _, method := range []string{ClusterGrid, ClusterDBSCAN} {
+		t.Run(method, func(t *testing.T) {
+			singles, clusters := clusterPlaces(places, ClusterOptions{Zoom: 10, Method: method, RadiusPx: 60})
+
+			if len(singles) != 1 || singles[0].PlaceID != "lone" {
+				t.Errorf("singles = %v, want just lone", singles)
+			}
+			if len(clusters) != 1 {
+				t.Fatalf("got %d clusters, want 1", len(clusters))
+			}
+			c := clusters[0]
+			if c.Count != 3 || c.Representative.PlaceID != "a" || !slices.Equal(c.PlaceIDs, []string{"a", "b", "c"}) {
+				t.Errorf("cluster = %d places %v led by %s, want 3 places [a b c] led by a", c.Count, c.PlaceIDs, c.Representative.PlaceID)
+			}
+			if want := (BBox{85, 19.999, 85.002, 20.001}); c.BBox != want {
+				t.Errorf("cluster bbox = %v, want %v", c.BBox, want)
+			}
+			if math.Abs(c.Centroid.Lat-20) > 1e-9 || math.Abs(c.Centroid.Lon-85.001) > 1e-9 {
+				t.Errorf("cluster centroid = %v, want 20, 85.001", c.Centroid)
+			}
+		})
+	}
+}
+
+func TestClusterOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ClusterOptions
+		want    ClusterOptions
+		wantErr bool
+	}{
+		{"defaults", ClusterOptions{Zoom: 12}, ClusterOptions{Zoom: 12, Method: ClusterGrid, RadiusPx: 60}, false},
+		{"dbscan", ClusterOptions{Zoom: 0, Method: ClusterDBSCAN, RadiusPx: 512}, ClusterOptions{Zoom: 0, Method: ClusterDBSCAN, RadiusPx: 512}, false},
+		{"max zoom", ClusterOptions{Zoom: 22, RadiusPx: 1}, ClusterOptions{Zoom: 22, Method: ClusterGrid, RadiusPx: 1}, false},
+		{"zoom too high", ClusterOptions{Zoom: 23}, ClusterOptions{}, true},
+		{"negative zoom", ClusterOptions{Zoom: -1}, ClusterOptions{}, true},
+		{"unknown method", ClusterOptions{Zoom: 12, Method: "kmeans"}, ClusterOptions{}, true},
+		{"radius too big", ClusterOptions{Zoom: 12, RadiusPx: 513}, ClusterOptions{}, true},
+		{"negative radius", ClusterOptions{Zoom: 12, RadiusPx: -5}, ClusterOptions{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			err := opts.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && opts != tt.want {
+				t.Errorf("Validate() left %+v, want %+v", opts, tt.want)
+			}
+		})
+	}
+}
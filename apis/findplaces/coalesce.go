@@ -0,0 +1,38 @@
+package findplaces
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// inflight dedupes concurrent upstream fetches that share a cache key, so a
+// group of users opening the app at the same site costs one Geoapify call.
+var inflight singleflight.Group
+
+// fetchTimeout bounds a whole coalesced fetch, retries included.
+const fetchTimeout = 20 * time.Second
+
+// fetchCoalesced runs fetch at most once per key at a time. Callers that miss
+// the cache while a fetch for the same key is running wait for it and receive
+// the same response. The fetch itself runs detached from the caller's
+// cancellation so one client disconnecting doesn't fail everybody waiting.
+func fetchCoalesced[T any](ctx context.Context, key string, fetch func(context.Context) (T, error)) (T, error) {
+	ch := inflight.DoChan(key, func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fetchTimeout)
+		defer cancel()
+		return fetch(ctx)
+	})
+
+	var zero T
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		return res.Val.(T), nil
+	}
+}
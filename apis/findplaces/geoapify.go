@@ -1,80 +1,437 @@
 package findplaces
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"sync"
+	"time"
 )
 
+const geoapifyBaseURL = "https://api.geoapify.com"
+
+type geoapifyFeature struct {
+	Properties struct {
+		PlaceID      string   `json:"place_id"`
+		Name         string   `json:"name"`
+		Street       string   `json:"street"`
+		City         string   `json:"city"`
+		State        string   `json:"state"`
+		Postcode     string   `json:"postcode"`
+		Country      string   `json:"country"`
+		Formatted    string   `json:"formatted"`
+		AddressLine1 string   `json:"address_line1"`
+		AddressLine2 string   `json:"address_line2"`
+		Distance     float64  `json:"distance"`
+		Categories   []string `json:"categories"`
+		Category     string   `json:"category"` // geocoder results carry one category
+		Lat          *float64 `json:"lat"`
+		Lon          *float64 `json:"lon"`
+
+		NameInternational map[string]string `json:"name_international"`
+		Datasource        struct {
+			Raw map[string]any `json:"raw"`
+		} `json:"datasource"`
+	} `json:"properties"`
+	Geometry struct {
+		Type string `json:"type"`
+		// Points carry [lon, lat]; details lookups may return polygons, in
+		// which case we rely on the lat/lon properties instead.
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+}
+
 type geoapifyResponse struct {
-	Features []struct {
-		Properties struct {
-			PlaceID      string   `json:"place_id"`
-			Name         string   `json:"name"`
-			Street       string   `json:"street"`
-			City         string   `json:"city"`
-			State        string   `json:"state"`
-			Postcode     string   `json:"postcode"`
-			Country      string   `json:"country"`
-			Formatted    string   `json:"formatted"`
-			AddressLine1 string   `json:"address_line1"`
-			AddressLine2 string   `json:"address_line2"`
-			Distance     float64  `json:"distance"`
-			Categories   []string `json:"categories"`
-		} `json:"properties"`
-		Geometry struct {
-			Coordinates []float64 `json:"coordinates"`
-		} `json:"geometry"`
-	} `json:"features"`
-}
-
-func FetchPlaces(lat, lon float64, radius, limit int, category string) ([]Place, error) {
-	apiKey := os.Getenv("GEOAPIFY_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("missing GEOAPIFY_API_KEY")
+	Features []geoapifyFeature `json:"features"`
+}
+
+// position returns the feature's lat/lon, preferring a Point geometry and
+// falling back to the lat/lon properties.
+func (f geoapifyFeature) position() (float64, float64, bool) {
+	if f.Geometry.Type == "Point" || f.Geometry.Type == "" {
+		var coords []float64
+		if err := json.Unmarshal(f.Geometry.Coordinates, &coords); err == nil && len(coords) >= 2 {
+			return coords[1], coords[0], validCoordinate(coords[1], coords[0])
+		}
+	}
+	if f.Properties.Lat != nil && f.Properties.Lon != nil {
+		lat, lon := *f.Properties.Lat, *f.Properties.Lon
+		return lat, lon, validCoordinate(lat, lon)
 	}
+	return 0, 0, false
+}
 
-	url := fmt.Sprintf(
-		"https://api.geoapify.com/v2/places?categories=%s&filter=circle:%.6f,%.6f,%d&limit=%d&apiKey=%s",
-		category, lon, lat, radius, limit, apiKey,
-	)
+func (f geoapifyFeature) toPlace() (Place, error) {
+	lat, lon, ok := f.position()
+	if !ok {
+		return Place{}, fmt.Errorf("bad coordinates %s", f.Geometry.Coordinates)
+	}
+	if f.Properties.PlaceID == "" {
+		return Place{}, errors.New("missing place_id")
+	}
 
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
+	categories := f.Properties.Categories
+	if len(categories) == 0 && f.Properties.Category != "" {
+		categories = []string{f.Properties.Category}
 	}
-	defer resp.Body.Close()
 
-	body, _ := io.ReadAll(resp.Body)
+	return Place{
+		PlaceID:        f.Properties.PlaceID,
+		Name:           f.Properties.Name,
+		Names:          f.localNames(),
+		Images:         f.images(),
+		Lat:            lat,
+		Lon:            lon,
+		Formatted:      f.Properties.Formatted,
+		Street:         f.Properties.Street,
+		AddressLine1:   f.Properties.AddressLine1,
+		AddressLine2:   f.Properties.AddressLine2,
+		City:           f.Properties.City,
+		State:          f.Properties.State,
+		Country:        f.Properties.Country,
+		Postcode:       f.Properties.Postcode,
+		Categories:     categories,
+		DistanceMeters: f.Properties.Distance,
+	}, nil
+}
 
+// parseFeatures decodes a Geoapify FeatureCollection, dropping (and logging)
+// features that fail validation rather than failing the whole response.
+func parseFeatures(body []byte) ([]Place, error) {
 	var geo geoapifyResponse
 	if err := json.Unmarshal(body, &geo); err != nil {
+		return nil, fmt.Errorf("decode geoapify response: %w", err)
+	}
+	if geo.Features == nil {
+		return nil, errors.New("geoapify response has no features array")
+	}
+
+	places := make([]Place, 0, len(geo.Features))
+	for i, f := range geo.Features {
+		p, err := f.toPlace()
+		if err != nil {
+			log.Printf("Skipping geoapify feature %d: %v", i, err)
+			continue
+		}
+		places = append(places, p)
+	}
+	return places, nil
+}
+
+// StatusError is returned when Geoapify answers with a non-200 status.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("geoapify returned %d: %s", e.StatusCode, e.Body)
+}
+
+// retryable reports whether the upstream may succeed if asked again.
+func (e *StatusError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Client talks to the Geoapify Places API. Every attempt is bounded by the
+// HTTP client's timeout and the caller's context; 429 and 5xx responses are
+// retried with jittered exponential backoff, and a circuit breaker stops us
+// hammering Geoapify while it is down.
+type Client struct {
+	HTTP       *http.Client
+	BaseURL    string
+	APIKey     string
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	Breaker    *CircuitBreaker
+	Usage      *UsageTracker
+}
+
+func NewClient(apiKey string) *Client {
+	return &Client{
+		HTTP:       &http.Client{Timeout: 8 * time.Second},
+		BaseURL:    geoapifyBaseURL,
+		APIKey:     apiKey,
+		MaxRetries: 3,
+		BaseDelay:  200 * time.Millisecond,
+		MaxDelay:   3 * time.Second,
+		Breaker:    NewCircuitBreaker(5, 30*time.Second),
+	}
+}
+
+var (
+	defaultClient     *Client
+	defaultClientOnce sync.Once
+)
+
+// DefaultClient returns the process-wide client configured from
+// GEOAPIFY_API_KEY, with usage counted against GEOAPIFY_DAILY_BUDGET.
+func DefaultClient() *Client {
+	defaultClientOnce.Do(func() {
+		defaultClient = NewClient(os.Getenv("GEOAPIFY_API_KEY"))
+		defaultClient.Usage = NewUsageTracker("geoapify", envInt("GEOAPIFY_DAILY_BUDGET", 0))
+		if err := defaultClient.Usage.Load(context.Background()); err != nil {
+			log.Printf("Loading Geoapify usage failed: %v", err)
+		}
+	})
+	return defaultClient
+}
+
+func FetchPlaces(ctx context.Context, lat, lon float64, radius, limit int, category string) ([]Place, error) {
+	return DefaultProvider().FetchPlaces(ctx, lat, lon, radius, limit, category)
+}
+
+func (c *Client) FetchPlaces(ctx context.Context, lat, lon float64, radius, limit int, category string) ([]Place, error) {
+	params := url.Values{}
+	params.Set("categories", category)
+	params.Set("filter", fmt.Sprintf("circle:%.6f,%.6f,%d", lon, lat, radius))
+	params.Set("limit", strconv.Itoa(limit))
+
+	body, err := c.get(ctx, "/v2/places", params)
+	if err != nil {
 		return nil, err
 	}
 
-	places := make([]Place, 0)
-	for _, f := range geo.Features {
-		p := Place{
-			PlaceID:        f.Properties.PlaceID,
-			Name:           f.Properties.Name,
-			Lat:            f.Geometry.Coordinates[1],
-			Lon:            f.Geometry.Coordinates[0],
-			Formatted:      f.Properties.Formatted,
-			Street:         f.Properties.Street,
-			AddressLine1:   f.Properties.AddressLine1,
-			AddressLine2:   f.Properties.AddressLine2,
-			City:           f.Properties.City,
-			State:          f.Properties.State,
-			Country:        f.Properties.Country,
-			Postcode:       f.Properties.Postcode,
-			Categories:     f.Properties.Categories,
-			DistanceMeters: f.Properties.Distance,
+	return parseFeatures(body)
+}
+
+func (c *Client) FetchPlacesInBox(ctx context.Context, box BBox, limit int, category string) ([]Place, error) {
+	params := url.Values{}
+	params.Set("categories", category)
+	params.Set("filter", fmt.Sprintf("rect:%.6f,%.6f,%.6f,%.6f", box.West(), box.South(), box.East(), box.North()))
+	params.Set("limit", strconv.Itoa(limit))
+
+	body, err := c.get(ctx, "/v2/places", params)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseFeatures(body)
+}
+
+// PlaceDetails looks a single place up by its Geoapify place_id.
+func (c *Client) PlaceDetails(ctx context.Context, placeID string) (Place, error) {
+	params := url.Values{}
+	params.Set("id", placeID)
+
+	body, err := c.get(ctx, "/v2/place-details", params)
+	if err != nil {
+		var se *StatusError
+		if errors.As(err, &se) && (se.StatusCode == http.StatusNotFound || se.StatusCode == http.StatusBadRequest) {
+			return Place{}, ErrPlaceNotFound
 		}
+		return Place{}, err
+	}
 
-		places = append(places, p)
+	places, err := parseFeatures(body)
+	if err != nil {
+		return Place{}, err
+	}
+	if len(places) == 0 {
+		return Place{}, ErrPlaceNotFound
 	}
+	return places[0], nil
+}
 
+// Search runs Geoapify's autocomplete geocoder over text.
+func (c *Client) Search(ctx context.Context, text string, near *Point, limit int) ([]Place, error) {
+	params := url.Values{}
+	params.Set("text", text)
+	params.Set("limit", strconv.Itoa(limit))
+	if near != nil {
+		params.Set("bias", fmt.Sprintf("proximity:%.6f,%.6f", near.Lon, near.Lat))
+	}
+
+	body, err := c.get(ctx, "/v1/geocode/autocomplete", params)
+	if err != nil {
+		return nil, err
+	}
+
+	places, err := parseFeatures(body)
+	if err != nil {
+		return nil, err
+	}
+	for i := range places {
+		// Addresses and localities come back without a name.
+		if places[i].Name == "" {
+			places[i].Name = places[i].AddressLine1
+		}
+	}
 	return places, nil
 }
+
+// Reverse runs Geoapify's reverse geocoder for a single coordinate.
+func (c *Client) Reverse(ctx context.Context, lat, lon float64) (Place, error) {
+	params := url.Values{}
+	params.Set("lat", strconv.FormatFloat(lat, 'f', 6, 64))
+	params.Set("lon", strconv.FormatFloat(lon, 'f', 6, 64))
+	params.Set("limit", "1")
+
+	body, err := c.get(ctx, "/v1/geocode/reverse", params)
+	if err != nil {
+		return Place{}, err
+	}
+
+	places, err := parseFeatures(body)
+	if err != nil {
+		return Place{}, err
+	}
+	if len(places) == 0 {
+		return Place{}, ErrPlaceNotFound
+	}
+	if places[0].Name == "" {
+		places[0].Name = places[0].AddressLine1
+	}
+	return places[0], nil
+}
+
+func (c *Client) Name() string { return "geoapify" }
+
+// get performs a GET against the Geoapify API, applying the circuit breaker
+// and retry policy, and returns the body of the first 200 response.
+func (c *Client) get(ctx context.Context, path string, params url.Values) ([]byte, error) {
+	if c.APIKey == "" {
+		return nil, fmt.Errorf("missing GEOAPIFY_API_KEY")
+	}
+	if c.Usage.Exhausted() {
+		return nil, ErrBudgetExhausted
+	}
+	if err := c.Breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	params.Set("apiKey", c.APIKey)
+	endpoint := c.BaseURL + path + "?" + params.Encode()
+
+	var lastErr error
+	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleepCtx(ctx, c.backoff(attempt, lastErr)); err != nil {
+				c.Breaker.Abandon()
+				return nil, err
+			}
+		}
+
+		body, err := c.do(ctx, endpoint)
+		if err == nil {
+			c.Breaker.Success()
+			return body, nil
+		}
+		lastErr = err
+
+		if ctx.Err() != nil {
+			// The caller gave up; that says nothing about upstream health.
+			c.Breaker.Abandon()
+			return nil, ctx.Err()
+		}
+		var se *StatusError
+		if errors.As(err, &se) && !se.retryable() {
+			c.Breaker.Success()
+			return nil, err
+		}
+	}
+
+	c.Breaker.Failure()
+	return nil, lastErr
+}
+
+func (c *Client) do(ctx context.Context, endpoint string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Usage.Record(ctx)
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		// url.Error carries the full URL, which includes our API key.
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			err = ue.Err
+		}
+		return nil, fmt.Errorf("geoapify request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, fmt.Errorf("read geoapify response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		se := &StatusError{StatusCode: resp.StatusCode, Body: truncate(string(body), 200)}
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return nil, &retryAfterError{StatusError: se, wait: d}
+		}
+		return nil, se
+	}
+
+	return body, nil
+}
+
+// backoff returns a full-jitter exponential delay, honouring Retry-After when
+// the upstream sent one.
+func (c *Client) backoff(attempt int, lastErr error) time.Duration {
+	var ra *retryAfterError
+	if errors.As(lastErr, &ra) && ra.wait <= c.MaxDelay {
+		return ra.wait
+	}
+
+	ceiling := c.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > c.MaxDelay {
+		ceiling = c.MaxDelay
+	}
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
+
+type retryAfterError struct {
+	*StatusError
+	wait time.Duration
+}
+
+func (e *retryAfterError) Unwrap() error { return e.StatusError }
+
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func validCoordinate(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "…"
+}
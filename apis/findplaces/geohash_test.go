@@ -0,0 +1,86 @@
+package findplaces
+
+import (
+	"strings"
+	"testing"
+)
+
+// geohashBounds decodes a geohash to the cell it names.
+func geohashBounds(t *testing.T, hash string) (south, west, north, east float64) {
+	t.Helper()
+	south, north, west, east = -90, 90, -180, 180
+	even := true
+	for _, c := range hash {
+		v := strings.IndexRune(geohashAlphabet, c)
+		if v < 0 {
+			t.Fatalf("geohash %q has invalid character %q", hash, c)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			on := v>>bit&1 == 1
+			if even {
+				if mid := (west + east) / 2; on {
+					west = mid
+				} else {
+					east = mid
+				}
+			} else {
+				if mid := (south + north) / 2; on {
+					south = mid
+				} else {
+					north = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return south, west, north, east
+}
+
+func TestGeohash(t *testing.T) {
+	tests := []struct {
+		name      string
+		lat, lon  float64
+		precision int
+		want      string
+	}{
+		{"reference point", 57.64911, 10.40744, 11, "u4pruydqqvj"},
+		{"reference point short", 42.6, -5.6, 5, "ezs42"},
+		{"origin", 0, 0, 7, "s000000"},
+		{"south-west corner", -90, -180, 5, "00000"},
+		{"north-east corner", 90, 180, 5, "zzzzz"},
+		{"just west of the antimeridian", 0, 179.9999, 3, "xbp"},
+		{"just east of the antimeridian", 0, -179.9999, 3, "800"},
+		{"zero precision", 20, 85, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Geohash(tt.lat, tt.lon, tt.precision); got != tt.want {
+				t.Errorf("Geohash(%v, %v, %d) = %q, want %q", tt.lat, tt.lon, tt.precision, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeohashCellContainsPoint(t *testing.T) {
+	points := []struct{ lat, lon float64 }{
+		{19.8876, 86.0945},
+		{-33.8568, 151.2153},
+		{40.6892, -74.0445},
+		{-0.0001, -0.0001},
+		{89.9999, -179.9999},
+	}
+	for _, p := range points {
+		full := Geohash(p.lat, p.lon, 9)
+		for precision := 1; precision <= 9; precision++ {
+			hash := Geohash(p.lat, p.lon, precision)
+			if !strings.HasPrefix(full, hash) {
+				t.Errorf("Geohash(%v, %v, %d) = %q, not a prefix of %q", p.lat, p.lon, precision, hash, full)
+			}
+			s, w, n, e := geohashBounds(t, hash)
+			if p.lat < s || p.lat > n || p.lon < w || p.lon > e {
+				t.Errorf("Geohash(%v, %v, %d) = %q, cell [%v,%v]x[%v,%v] misses the point",
+					p.lat, p.lon, precision, hash, s, n, w, e)
+			}
+		}
+	}
+}
@@ -1,90 +1,440 @@
 package findplaces
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"example.com/m/db"
+	"example.com/m/geoformat"
+	"example.com/m/i18n"
+	"example.com/m/utils"
+	"github.com/go-chi/chi/v5"
 )
 
+// searchLimiter caps how often a single user may search, configured by
+// FINDPLACES_SEARCHES_PER_MINUTE (0 disables the limit).
+var searchLimiter = EnvLimiter("FINDPLACES_SEARCHES_PER_MINUTE", 30)
+
+// autocompleteLimiter caps how often one user's keystrokes may reach the
+// provider; beyond it they still get catalog results.
+var autocompleteLimiter = EnvLimiter("FINDPLACES_AUTOCOMPLETE_PER_MINUTE", 60)
+
 // Handler finds places based on location
 // @Summary Find Places
-// @Description Search for places based on latitude, longitude, and radius
+// @Description Search for places around latitude/longitude within a radius, or inside a bounding box ([west, south, east, north]; west > east crosses the antimeridian) or GeoJSON polygon
 // @Tags places
 // @Accept json
 // @Produce json
+// @Produce application/geo+json
+// @Produce application/gpx+xml
+// @Produce application/vnd.google-earth.kml+xml
 // @Security BearerAuth
 // @Param request body FindPlacesRequest true "Search Criteria"
+// @Param format query string false "Output format: json, geojson, gpx or kml (overrides Accept)"
+// @Param Accept-Language header string false "Preferred languages for place names and messages, e.g. or, hi;q=0.8"
 // @Success 200 {object} FindPlacesResponse
 // @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 429 {object} map[string]string "Too many searches"
+// @Failure 503 {object} map[string]string "Daily search budget exhausted"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /findplaces [post]
 func Handler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	if r.Method != http.MethodPost {
-		http.Error(w, `{"error":"Only POST allowed"}`, http.StatusMethodNotAllowed)
+		i18n.JSONError(w, r, i18n.MethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.JSONError(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	if ok, wait := searchLimiter().Allow(userUUID); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		i18n.JSONError(w, r, i18n.TooManySearches, http.StatusTooManyRequests)
 		return
 	}
 
 	var req FindPlacesRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error":"Invalid JSON"}`, http.StatusBadRequest)
+		i18n.JSONError(w, r, i18n.InvalidJSON, http.StatusBadRequest)
 		return
 	}
 
 	// Basic validation
-	if req.Latitude == 0 || req.Longitude == 0 {
-		http.Error(w, `{"error":"Latitude and Longitude required"}`, http.StatusBadRequest)
-		return
-	}
 	if req.RadiusMeters <= 0 {
 		req.RadiusMeters = 5000 // default radius = 5km
 	}
 	if req.Limit <= 0 || req.Limit > 50 {
 		req.Limit = 20
 	}
+	if req.Sort == "" {
+		req.Sort = SortDistance
+	}
+	if !validSort(req.Sort) {
+		i18n.JSONError(w, r, i18n.InvalidSort, http.StatusBadRequest)
+		return
+	}
+	area, err := req.searchArea()
+	if err != nil {
+		i18n.JSONError(w, r, i18n.InvalidArea, http.StatusBadRequest)
+		return
+	}
+	if req.Cluster != nil {
+		if err := req.Cluster.Validate(); err != nil {
+			i18n.JSONError(w, r, i18n.InvalidCluster, http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Distances are measured from the caller when we know where they are,
+	// otherwise from the middle of the area.
+	origin := area.center
+	if req.Latitude != 0 && req.Longitude != 0 {
+		origin = Point{Lat: req.Latitude, Lon: req.Longitude}
+	}
+	langs := i18n.Languages(r)
+	w.Header().Set("Vary", "Accept, Accept-Language")
+	arrange := func(res FindPlacesResponse) FindPlacesResponse {
+		res.Places = arrangePlaces(res.Places, origin, req.Sort, req.Limit)
+		if err := AttachRatings(r.Context(), db.Conn, res.Places); err != nil {
+			log.Printf("Loading ratings failed: %v", err)
+		}
+		localizePlaces(res.Places, langs)
+		if req.Cluster != nil {
+			res.Places, res.Clusters = clusterPlaces(res.Places, *req.Cluster)
+		}
+		return res
+	}
 
 	category := "tourism"
-	cacheKey := BuildCacheKey(req.Latitude, req.Longitude, req.RadiusMeters, category)
+	cacheKey := area.cacheKey(category)
 
 	// ---- CACHE CHECK ----
-	if cachedRes, cachedLat, cachedLon, ok := GetCachedResponse(r.Context(), db.Conn, cacheKey); ok {
-		// Reuse if user is close to cached coordinates (~100 meters)
-		if Haversine(cachedLat, cachedLon, req.Latitude, req.Longitude) < 100 {
-			json.NewEncoder(w).Encode(cachedRes)
+	// Circle searches reuse the nearest cached search of the same radius
+	// within ~100 meters. Area queries are keyed on the exact area.
+	var cached CachedEntry
+	var hit bool
+	if area.isCircle() {
+		cached, hit = FindNearbyCachedResponse(r.Context(), db.Conn,
+			area.center.Lat, area.center.Lon, area.radius, category, cacheReuseDistance)
+	} else {
+		cached, hit = GetCachedResponse(r.Context(), db.Conn, cacheKey)
+	}
+	if hit && !cached.Expired() {
+		writePlaces(w, r, arrange(cached.Response))
+		return
+	}
+
+	// ---- FETCH FROM GEOAPIFY (coalesced per cache key) ----
+	response, err := fetchCoalesced(r.Context(), cacheKey, func(ctx context.Context) (FindPlacesResponse, error) {
+		places, err := area.fetch(ctx, DefaultProvider(), req.Limit, category)
+		if err != nil {
+			return FindPlacesResponse{}, err
+		}
+
+		response := FindPlacesResponse{
+			Places:      places,
+			GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		}
+
+		// ---- SAVE TO CACHE (best-effort) ----
+		if err := CacheResponse(
+			ctx,
+			db.Conn,
+			cacheKey,
+			area.center.Lat,
+			area.center.Lon,
+			area.radius,
+			category,
+			area.kind(),
+			response,
+		); err != nil {
+			log.Printf("Cache save failed: %v", err)
+		}
+		if err := UpsertPlaces(ctx, db.Conn, DefaultProvider().Name(), places); err != nil {
+			log.Printf("Catalog save failed: %v", err)
+		}
+
+		return response, nil
+	})
+	if err != nil {
+		log.Printf("Geoapify fetch failed: %v", err)
+
+		// Upstream is down: an expired answer beats no answer.
+		if hit {
+			cached.Response.Stale = true
+			writePlaces(w, r, arrange(cached.Response))
+			return
+		}
+
+		// Nothing cached here either: fall back to what the catalog knows.
+		if places := catalogPlaces(r.Context(), area, category); len(places) > 0 {
+			writePlaces(w, r, arrange(FindPlacesResponse{
+				Places:      places,
+				GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+				Stale:       true,
+			}))
 			return
 		}
+
+		if errors.Is(err, ErrBudgetExhausted) {
+			i18n.JSONError(w, r, i18n.BudgetExhausted, http.StatusServiceUnavailable)
+			return
+		}
+		i18n.JSONError(w, r, i18n.FetchPlacesFailed, http.StatusBadGateway)
+		return
 	}
 
-	// ---- FETCH FROM GEOAPIFY ----
-	places, err := FetchPlaces(req.Latitude, req.Longitude, req.RadiusMeters, req.Limit, category)
+	writePlaces(w, r, arrange(response))
+}
+
+// catalogPlaces returns catalog places of category inside area, or nil.
+func catalogPlaces(ctx context.Context, area searchArea, category string) []Place {
+	candidates, err := PlacesWithin(ctx, db.Conn, area.center.Lat, area.center.Lon, float64(area.radius), 500)
 	if err != nil {
-		log.Printf("Geoapify fetch failed: %v", err)
-		http.Error(w, `{"error":"Failed fetching places"}`, http.StatusBadGateway)
+		log.Printf("Catalog fallback failed: %v", err)
+		return nil
+	}
+
+	var places []Place
+	for _, p := range candidates {
+		if area.contains(p.Lat, p.Lon) && hasCategory(p, category) {
+			places = append(places, p)
+		}
+	}
+	return places
+}
+
+// writePlaces encodes a response in the format the client negotiated.
+func writePlaces(w http.ResponseWriter, r *http.Request, res FindPlacesResponse) {
+	f := geoformat.Negotiate(r)
+	if f == geoformat.JSON {
+		json.NewEncoder(w).Encode(res)
+		return
+	}
+
+	if res.Stale {
+		w.Header().Set("Warning", `110 - "Response is Stale"`)
+	}
+	features := make([]geoformat.Feature, 0, len(res.Places)+len(res.Clusters))
+	for _, p := range res.Places {
+		features = append(features, p.Feature())
+	}
+	for _, c := range res.Clusters {
+		features = append(features, c.Feature())
+	}
+	if err := geoformat.Write(w, f, "Places", features); err != nil {
+		log.Printf("Writing %s failed: %v", f.MediaType(), err)
+	}
+}
+
+// PlaceHandler returns a single place from the catalog
+// @Summary Get Place
+// @Description Look up a place by ID, falling back to the provider if it isn't in the catalog yet
+// @Tags places
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Place ID"
+// @Param Accept-Language header string false "Preferred languages for place names and messages, e.g. or, hi;q=0.8"
+// @Success 200 {object} Place
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Place not found"
+// @Failure 502 {object} map[string]string "Provider lookup failed"
+// @Failure 503 {object} map[string]string "Daily search budget exhausted"
+// @Router /places/{id} [get]
+func PlaceHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	placeID := chi.URLParam(r, "id")
+	if placeID == "" {
+		i18n.JSONError(w, r, i18n.MissingPlaceID, http.StatusBadRequest)
 		return
 	}
 
-	response := FindPlacesResponse{
-		Places:      places,
-		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+	place, err := LookupPlace(r.Context(), db.Conn, DefaultProvider(), placeID)
+	if err != nil {
+		if errors.Is(err, ErrPlaceNotFound) {
+			i18n.JSONError(w, r, i18n.PlaceNotFound, http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, ErrBudgetExhausted) {
+			i18n.JSONError(w, r, i18n.BudgetExhausted, http.StatusServiceUnavailable)
+			return
+		}
+		log.Printf("Place lookup failed: %v", err)
+		i18n.JSONError(w, r, i18n.FetchPlaceFailed, http.StatusBadGateway)
+		return
 	}
 
-	// ---- SAVE TO CACHE (async best-effort) ----
-	if err := CacheResponse(
-		r.Context(),
-		db.Conn,
-		cacheKey,
-		req.Latitude,
-		req.Longitude,
-		req.RadiusMeters,
-		category,
-		response,
-	); err != nil {
-		log.Printf("Cache save failed: %v", err)
+	w.Header().Set("Vary", "Accept-Language")
+	place.Localize(i18n.Languages(r))
+	json.NewEncoder(w).Encode(place)
+}
+
+// SearchHandler searches places by name
+// @Summary Search Places
+// @Description Prefix autocomplete and fuzzy search over the places catalog, topped up with the provider's geocoder
+// @Tags places
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search text (at least 2 characters)"
+// @Param lat query number false "Caller latitude, to bias results nearby"
+// @Param lon query number false "Caller longitude, to bias results nearby"
+// @Param limit query int false "Maximum results (default 10, max 25)"
+// @Param Accept-Language header string false "Preferred languages for place names and messages, e.g. or, hi;q=0.8"
+// @Success 200 {object} SearchResponse
+// @Failure 400 {object} map[string]string "Invalid input"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /places/search [get]
+func SearchHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.JSONError(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	q := strings.TrimSpace(query.Get("q"))
+	if len([]rune(q)) < 2 {
+		i18n.JSONError(w, r, i18n.QueryTooShort, http.StatusBadRequest)
+		return
+	}
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 || limit > 25 {
+		limit = 10
+	}
+
+	var near *Point
+	if query.Get("lat") != "" || query.Get("lon") != "" {
+		lat, latErr := strconv.ParseFloat(query.Get("lat"), 64)
+		lon, lonErr := strconv.ParseFloat(query.Get("lon"), 64)
+		if latErr != nil || lonErr != nil || !validCoordinate(lat, lon) {
+			i18n.JSONError(w, r, i18n.InvalidLatLon, http.StatusBadRequest)
+			return
+		}
+		near = &Point{Lat: lat, Lon: lon}
+	}
+
+	places, err := SearchCatalog(r.Context(), db.Conn, q, near, limit)
+	if err != nil {
+		log.Printf("Catalog search failed: %v", err)
+		i18n.JSONError(w, r, i18n.SearchFailed, http.StatusInternalServerError)
+		return
+	}
+
+	// Top up from the provider when the catalog doesn't have enough. Any
+	// upstream trouble just means catalog-only results.
+	if len(places) < limit && len([]rune(q)) >= 3 {
+		if ok, _ := autocompleteLimiter().Allow(userUUID); ok {
+			provider := DefaultProvider()
+			remote, err := provider.Search(r.Context(), q, near, limit)
+			if err != nil {
+				log.Printf("Provider search failed: %v", err)
+			} else {
+				if near != nil {
+					for i := range remote {
+						remote[i].DistanceMeters = Haversine(near.Lat, near.Lon, remote[i].Lat, remote[i].Lon)
+					}
+				}
+				if err := UpsertPlaces(r.Context(), db.Conn, provider.Name(), pointsOfInterest(remote)); err != nil {
+					log.Printf("Catalog save failed: %v", err)
+				}
+				places = mergePlaces(places, remote, limit)
+			}
+		}
+	}
+
+	if err := AttachRatings(r.Context(), db.Conn, places); err != nil {
+		log.Printf("Loading ratings failed: %v", err)
+	}
+	w.Header().Set("Vary", "Accept-Language")
+	localizePlaces(places, i18n.Languages(r))
+	json.NewEncoder(w).Encode(SearchResponse{Query: q, Results: places})
+}
+
+// ReverseHandler reverse geocodes a coordinate
+// @Summary Reverse Geocode
+// @Description Resolve a coordinate to its address (city, state, country, formatted) in the same shape as a place
+// @Tags places
+// @Produce json
+// @Security BearerAuth
+// @Param lat query number true "Latitude"
+// @Param lon query number true "Longitude"
+// @Param Accept-Language header string false "Preferred languages for place names and messages, e.g. or, hi;q=0.8"
+// @Success 200 {object} Place
+// @Failure 400 {object} map[string]string "Invalid lat/lon"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Nothing found at this location"
+// @Failure 502 {object} map[string]string "Provider lookup failed"
+// @Router /geo/reverse [get]
+func ReverseHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		i18n.JSONError(w, r, i18n.MethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+
+	lat, latErr := strconv.ParseFloat(r.URL.Query().Get("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(r.URL.Query().Get("lon"), 64)
+	if latErr != nil || lonErr != nil || !validCoordinate(lat, lon) {
+		i18n.JSONError(w, r, i18n.InvalidLatLon, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Vary", "Accept-Language")
+	langs := i18n.Languages(r)
+
+	hash := Geohash(lat, lon, reverseGeohashPrecision)
+	cached, hit, expired := GetCachedReverse(r.Context(), db.Conn, hash)
+	if hit && !expired {
+		cached.Localize(langs)
+		json.NewEncoder(w).Encode(cached)
+		return
+	}
+
+	place, err := fetchCoalesced(r.Context(), "reverse:"+hash, func(ctx context.Context) (Place, error) {
+		place, err := DefaultProvider().Reverse(ctx, lat, lon)
+		if err != nil {
+			return Place{}, err
+		}
+		// Shared by everyone in the cell, so drop the distance to this caller.
+		place.DistanceMeters = 0
+
+		if err := CacheReverse(ctx, db.Conn, hash, place); err != nil {
+			log.Printf("Reverse cache save failed: %v", err)
+		}
+		return place, nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrPlaceNotFound) {
+			i18n.JSONError(w, r, i18n.NothingFound, http.StatusNotFound)
+			return
+		}
+		log.Printf("Reverse geocode failed: %v", err)
+		if hit {
+			cached.Localize(langs)
+			json.NewEncoder(w).Encode(cached)
+			return
+		}
+		i18n.JSONError(w, r, i18n.ReverseFailed, http.StatusBadGateway)
+		return
 	}
 
-	json.NewEncoder(w).Encode(response)
+	place.Localize(langs)
+	json.NewEncoder(w).Encode(place)
 }
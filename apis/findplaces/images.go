@@ -0,0 +1,37 @@
+package findplaces
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Image sources.
+const (
+	ImageSourceOSM       = "osm"
+	ImageSourceWikimedia = "wikimedia_commons"
+)
+
+// Image describes a picture of a place hosted elsewhere.
+type Image struct {
+	URL    string `json:"url"`
+	Source string `json:"source" enums:"osm,wikimedia_commons"`
+}
+
+// images collects image references from the feature's raw OSM tags: a
+// direct image URL and/or a Wikimedia Commons file.
+func (f geoapifyFeature) images() []Image {
+	var images []Image
+	raw := f.Properties.Datasource.Raw
+
+	if v, _ := raw["image"].(string); strings.HasPrefix(v, "https://") || strings.HasPrefix(v, "http://") {
+		images = append(images, Image{URL: v, Source: ImageSourceOSM})
+	}
+	if v, _ := raw["wikimedia_commons"].(string); strings.HasPrefix(v, "File:") {
+		name := strings.ReplaceAll(strings.TrimPrefix(v, "File:"), " ", "_")
+		images = append(images, Image{
+			URL:    "https://commons.wikimedia.org/wiki/Special:FilePath/" + url.PathEscape(name),
+			Source: ImageSourceWikimedia,
+		})
+	}
+	return images
+}
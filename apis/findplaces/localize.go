@@ -0,0 +1,61 @@
+package findplaces
+
+import "strings"
+
+// localNames collects a feature's per-language names from Geoapify's
+// name_international and the raw OSM name:<lang> tags, keyed by primary
+// language subtag.
+func (f geoapifyFeature) localNames() map[string]string {
+	names := map[string]string{}
+	for key, v := range f.Properties.Datasource.Raw {
+		lang, ok := strings.CutPrefix(key, "name:")
+		if name, isString := v.(string); ok && isString {
+			addName(names, lang, name)
+		}
+	}
+	for lang, name := range f.Properties.NameInternational {
+		addName(names, lang, name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return names
+}
+
+// addName records name under lang's primary subtag, letting a plain tag
+// ("zh") win over a regional one ("zh-Hant").
+func addName(names map[string]string, lang, name string) {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	name = strings.TrimSpace(name)
+	if lang == "" || name == "" {
+		return
+	}
+	base, _, regional := strings.Cut(lang, "-")
+	if _, exists := names[base]; exists && regional {
+		return
+	}
+	names[base] = name
+}
+
+// LocalName returns the name for the first of langs that names covers, or
+// fallback.
+func LocalName(names map[string]string, fallback string, langs []string) string {
+	for _, lang := range langs {
+		if name := names[lang]; name != "" {
+			return name
+		}
+	}
+	return fallback
+}
+
+// Localize replaces Name with the place's name in the caller's preferred
+// language, when the catalog has one.
+func (p *Place) Localize(langs []string) {
+	p.Name = LocalName(p.Names, p.Name, langs)
+}
+
+func localizePlaces(places []Place, langs []string) {
+	for i := range places {
+		places[i].Localize(langs)
+	}
+}
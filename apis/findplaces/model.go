@@ -1,30 +1,66 @@
 package findplaces
 
+import "example.com/m/geoformat"
+
 type Place struct {
-	PlaceID        string   `json:"id"`
-	Name           string   `json:"name"`
-	Lat            float64  `json:"lat"`
-	Lon            float64  `json:"lon"`
-	AddressLine1   string   `json:"address_line1"`
-	AddressLine2   string   `json:"address_line2"`
-	Formatted      string   `json:"formatted"`
-	Street         string   `json:"street"`
-	City           string   `json:"city"`
-	State          string   `json:"state"`
-	Country        string   `json:"country"`
-	Postcode       string   `json:"postcode"`
-	Categories     []string `json:"categories"`
-	DistanceMeters float64  `json:"distance_meters"`
+	PlaceID string `json:"id"`
+	Name    string `json:"name"`
+	// Names holds the place's name per language code, where known.
+	Names          map[string]string `json:"names,omitempty"`
+	Lat            float64           `json:"lat"`
+	Lon            float64           `json:"lon"`
+	AddressLine1   string            `json:"address_line1"`
+	AddressLine2   string            `json:"address_line2"`
+	Formatted      string            `json:"formatted"`
+	Street         string            `json:"street"`
+	City           string            `json:"city"`
+	State          string            `json:"state"`
+	Country        string            `json:"country"`
+	Postcode       string            `json:"postcode"`
+	Categories     []string          `json:"categories"`
+	DistanceMeters float64           `json:"distance_meters"`
+	// Images are pictures of the place hosted elsewhere, where known.
+	Images []Image `json:"images,omitempty"`
+	// Rating is the average review rating (1-5), unset until reviewed.
+	Rating      *float64 `json:"rating,omitempty"`
+	RatingCount int      `json:"rating_count"`
+}
+
+func (p Place) Feature() geoformat.Feature {
+	return geoformat.Feature{
+		ID:          p.PlaceID,
+		Name:        p.Name,
+		Lat:         p.Lat,
+		Lon:         p.Lon,
+		Description: p.Formatted,
+		Categories:  p.Categories,
+		Properties:  p,
+	}
 }
 
+// FindPlacesRequest searches a circle around latitude/longitude by default.
+// Sending bbox or polygon instead searches that area.
 type FindPlacesRequest struct {
-	Latitude     float64 `json:"latitude"`
-	Longitude    float64 `json:"longitude"`
-	RadiusMeters int     `json:"radius_meters"`
-	Limit        int     `json:"limit"`
+	Latitude     float64         `json:"latitude"`
+	Longitude    float64         `json:"longitude"`
+	RadiusMeters int             `json:"radius_meters"`
+	Limit        int             `json:"limit"`
+	Sort         string          `json:"sort,omitempty" enums:"distance,relevance,name" default:"distance"`
+	BBox         *BBox           `json:"bbox,omitempty" swaggertype:"array,number" example:"85.0,19.8,85.2,19.9"`
+	Polygon      *Polygon        `json:"polygon,omitempty"`
+	Cluster      *ClusterOptions `json:"cluster,omitempty"`
 }
 
+// FindPlacesResponse lists places; when clustering was requested, places
+// that merged are in Clusters instead.
 type FindPlacesResponse struct {
-	Places      []Place `json:"places"`
-	GeneratedAt string  `json:"generated_at"`
+	Places      []Place   `json:"places"`
+	Clusters    []Cluster `json:"clusters,omitempty"`
+	GeneratedAt string    `json:"generated_at"`
+	Stale       bool      `json:"stale,omitempty"`
+}
+
+type SearchResponse struct {
+	Query   string  `json:"query"`
+	Results []Place `json:"results"`
 }
@@ -0,0 +1,34 @@
+package findplaces
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrPlaceNotFound = errors.New("place not found")
+
+// Point is a WGS84 coordinate.
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Provider is an upstream source of places. Geoapify is the only one today;
+// the rest of the package talks to this interface so another source can be
+// slotted in without touching handlers.
+type Provider interface {
+	Name() string
+	FetchPlaces(ctx context.Context, lat, lon float64, radius, limit int, category string) ([]Place, error)
+	// FetchPlacesInBox expects a box that doesn't cross the antimeridian.
+	FetchPlacesInBox(ctx context.Context, box BBox, limit int, category string) ([]Place, error)
+	// PlaceDetails returns ErrPlaceNotFound if the provider doesn't know id.
+	PlaceDetails(ctx context.Context, id string) (Place, error)
+	// Search geocodes free text, biased towards near when it is set.
+	Search(ctx context.Context, text string, near *Point, limit int) ([]Place, error)
+	// Reverse returns the address at a coordinate, or ErrPlaceNotFound.
+	Reverse(ctx context.Context, lat, lon float64) (Place, error)
+}
+
+func DefaultProvider() Provider {
+	return DefaultClient()
+}
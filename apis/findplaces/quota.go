@@ -0,0 +1,140 @@
+package findplaces
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"example.com/m/db"
+)
+
+// ErrBudgetExhausted is returned instead of calling upstream once the daily
+// budget is spent; callers fall back to whatever is cached.
+var ErrBudgetExhausted = errors.New("daily upstream budget exhausted")
+
+// UsageTracker counts billable upstream calls per UTC day in api_usage and
+// enforces an optional daily budget. The current day's count is mirrored in
+// memory so budget checks don't cost a query.
+type UsageTracker struct {
+	Provider    string
+	DailyBudget int // 0 means unlimited
+
+	mu    sync.Mutex
+	day   string
+	calls int
+}
+
+func NewUsageTracker(provider string, dailyBudget int) *UsageTracker {
+	return &UsageTracker{Provider: provider, DailyBudget: dailyBudget}
+}
+
+func today() string {
+	return time.Now().UTC().Format(time.DateOnly)
+}
+
+// Record counts one upstream request.
+func (u *UsageTracker) Record(ctx context.Context) {
+	if u == nil {
+		return
+	}
+	day := today()
+
+	var calls int
+	err := db.Conn.QueryRow(ctx,
+		`INSERT INTO api_usage(provider, day, calls) VALUES($1, $2, 1)
+		 ON CONFLICT(provider, day) DO UPDATE SET calls = api_usage.calls + 1
+		 RETURNING calls`,
+		u.Provider, day,
+	).Scan(&calls)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.day != day {
+		u.day, u.calls = day, 0
+	}
+	if err != nil {
+		log.Printf("Usage record failed: %v", err)
+		u.calls++
+		return
+	}
+	u.calls = calls
+}
+
+// Exhausted reports whether today's budget has been used up.
+func (u *UsageTracker) Exhausted() bool {
+	if u == nil || u.DailyBudget <= 0 {
+		return false
+	}
+	return u.Today() >= u.DailyBudget
+}
+
+// Today returns the number of upstream calls made so far today.
+func (u *UsageTracker) Today() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.day != today() {
+		return 0
+	}
+	return u.calls
+}
+
+// Load primes the in-memory counter from the database, so a restart doesn't
+// hand out a fresh budget.
+func (u *UsageTracker) Load(ctx context.Context) error {
+	day := today()
+
+	var calls int
+	err := db.Conn.QueryRow(ctx,
+		`SELECT COALESCE(SUM(calls), 0) FROM api_usage WHERE provider=$1 AND day=$2`,
+		u.Provider, day,
+	).Scan(&calls)
+	if err != nil {
+		return err
+	}
+
+	u.mu.Lock()
+	u.day, u.calls = day, calls
+	u.mu.Unlock()
+	return nil
+}
+
+type DailyUsage struct {
+	Provider string `json:"provider"`
+	Day      string `json:"day"`
+	Calls    int    `json:"calls"`
+}
+
+func GetUsage(ctx context.Context, days int) ([]DailyUsage, error) {
+	rows, err := db.Conn.Query(ctx,
+		`SELECT provider, to_char(day, 'YYYY-MM-DD'), calls FROM api_usage
+		 WHERE day > CURRENT_DATE - $1::int
+		 ORDER BY day DESC, provider`,
+		days,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := make([]DailyUsage, 0)
+	for rows.Next() {
+		var u DailyUsage
+		if err := rows.Scan(&u.Provider, &u.Day, &u.Calls); err == nil {
+			usage = append(usage, u)
+		}
+	}
+	return usage, rows.Err()
+}
+
+func envInt(name string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(name)); err == nil {
+		return v
+	}
+	return fallback
+}
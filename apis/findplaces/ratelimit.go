@@ -0,0 +1,81 @@
+package findplaces
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter is a per-key token bucket: each key (a user, or a client address
+// for public endpoints) may burst up to perMinute requests and then gets one
+// more every 60s/perMinute.
+type Limiter struct {
+	perMinute int
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func NewLimiter(perMinute int) *Limiter {
+	return &Limiter{perMinute: perMinute, buckets: make(map[string]*bucket)}
+}
+
+// EnvLimiter returns a getter for a limiter configured from the environment
+// variable name. The limiter is built on first use rather than at package
+// init, which runs before main has loaded .env.
+func EnvLimiter(name string, fallback int) func() *Limiter {
+	return sync.OnceValue(func() *Limiter {
+		return NewLimiter(envInt(name, fallback))
+	})
+}
+
+// Allow takes a token for key. When none is left it returns false and how
+// long until the next one is available.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if l.perMinute <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	rate := float64(l.perMinute) / 60 // tokens per second
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.perMinute), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = min(float64(l.perMinute), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep drops buckets that have refilled completely, so idle keys don't
+// accumulate in memory.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= time.Minute {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package findplaces
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AttachRatings fills in current review ratings from the catalog. Provider
+// results and cached responses don't carry them, or carry old ones.
+func AttachRatings(ctx context.Context, conn *pgxpool.Pool, places []Place) error {
+	if len(places) == 0 {
+		return nil
+	}
+	ids := make([]string, len(places))
+	for i, p := range places {
+		ids[i] = p.PlaceID
+	}
+
+	rows, err := conn.Query(ctx,
+		`SELECT place_id, rating_avg, rating_count FROM places
+		 WHERE place_id = ANY($1) AND rating_count > 0`,
+		ids,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type rating struct {
+		avg   *float64
+		count int
+	}
+	ratings := map[string]rating{}
+	for rows.Next() {
+		var id string
+		var r rating
+		if err := rows.Scan(&id, &r.avg, &r.count); err == nil {
+			ratings[id] = r
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range places {
+		r := ratings[places[i].PlaceID]
+		places[i].Rating, places[i].RatingCount = r.avg, r.count
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package findplaces
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	SortDistance  = "distance"
+	SortRelevance = "relevance" // provider order
+	SortName      = "name"
+)
+
+func validSort(s string) bool {
+	return s == SortDistance || s == SortRelevance || s == SortName
+}
+
+// duplicateRadius is how close two same-named places must be to count as one
+// place listed under different IDs (e.g. by different providers).
+const duplicateRadius = 50.0
+
+// arrangePlaces prepares places for one caller: distances are recomputed
+// from origin (a cached response may have been fetched for someone else),
+// duplicates are dropped, the list is sorted and cut to limit. places is not
+// modified, since cached and coalesced responses are shared between requests.
+func arrangePlaces(places []Place, origin Point, sortBy string, limit int) []Place {
+	out := make([]Place, 0, len(places))
+	for _, p := range places {
+		if isDuplicate(out, p) {
+			continue
+		}
+		p.DistanceMeters = Haversine(origin.Lat, origin.Lon, p.Lat, p.Lon)
+		out = append(out, p)
+	}
+
+	switch sortBy {
+	case SortDistance:
+		sort.SliceStable(out, func(i, j int) bool { return out[i].DistanceMeters < out[j].DistanceMeters })
+	case SortName:
+		sort.SliceStable(out, func(i, j int) bool { return normalizeName(out[i].Name) < normalizeName(out[j].Name) })
+	}
+
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+func isDuplicate(kept []Place, p Place) bool {
+	name := normalizeName(p.Name)
+	for _, k := range kept {
+		if k.PlaceID == p.PlaceID {
+			return true
+		}
+		if name != "" && normalizeName(k.Name) == name &&
+			Haversine(k.Lat, k.Lon, p.Lat, p.Lon) < duplicateRadius {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeName lowercases and strips punctuation and spacing so "Sun Temple,
+// Konark" and "sun temple konark" compare equal. Combining marks are kept:
+// in Indic scripts they are vowels, and dropping them makes different names
+// collide.
+func normalizeName(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if isWordRune(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// hasCategory reports whether p is in category or one of its subcategories.
+func hasCategory(p Place, category string) bool {
+	for _, c := range p.Categories {
+		if c == category || strings.HasPrefix(c, category+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package findplaces
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Sun Temple, Konark", "suntemplekonark"},
+		{"  sun-temple   KONARK ", "suntemplekonark"},
+		{"St. Mary's", "stmarys"},
+		{"Café 24", "café24"},
+		{"सूर्य मंदिर", "सूर्यमंदिर"},
+		{"ସୂର୍ଯ୍ୟ ମନ୍ଦିର", "ସୂର୍ଯ୍ୟମନ୍ଦିର"},
+		{"!!!", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeName(tt.in); got != tt.want {
+			t.Errorf("normalizeName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeNameKeepsIndicNamesApart(t *testing.T) {
+	// Each pair differs only in its vowel signs or virama.
+	pairs := [][2]string{
+		{"मंदिर", "मदर"},
+		{"कोणार्क", "कोणर्क"},
+		{"पुरी", "पुर"},
+		{"ମନ୍ଦିର", "ମନଦର"},
+		{"ପୁରୀ", "ପର"},
+	}
+	for _, p := range pairs {
+		if a, b := normalizeName(p[0]), normalizeName(p[1]); a == b {
+			t.Errorf("normalizeName(%q) and normalizeName(%q) are both %q", p[0], p[1], a)
+		}
+	}
+}
+
+func TestArrangePlacesDedupe(t *testing.T) {
+	origin := Point{Lat: 19.8876, Lon: 86.0945}
+	tests := []struct {
+		name   string
+		places []Place
+		want   []string
+	}{
+		{
+			name: "same id",
+			places: []Place{
+				{PlaceID: "a", Name: "Sun Temple", Lat: 19.8876, Lon: 86.0945},
+				{PlaceID: "a", Name: "Sun Temple", Lat: 19.8876, Lon: 86.0945},
+			},
+			want: []string{"a"},
+		},
+		{
+			name: "same name nearby",
+			places: []Place{
+				{PlaceID: "a", Name: "Sun Temple, Konark", Lat: 19.8876, Lon: 86.0945},
+				{PlaceID: "b", Name: "sun temple konark", Lat: 19.8877, Lon: 86.0945},
+			},
+			want: []string{"a"},
+		},
+		{
+			name: "same name far apart",
+			places: []Place{
+				{PlaceID: "a", Name: "Sun Temple", Lat: 19.8876, Lon: 86.0945},
+				{PlaceID: "b", Name: "Sun Temple", Lat: 19.9, Lon: 86.0945},
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "hindi names differing in vowel signs",
+			places: []Place{
+				{PlaceID: "a", Name: "मंदिर", Lat: 19.8876, Lon: 86.0945},
+				{PlaceID: "b", Name: "मदर", Lat: 19.8876, Lon: 86.0945},
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "odia names differing in virama",
+			places: []Place{
+				{PlaceID: "a", Name: "ମନ୍ଦିର", Lat: 19.8876, Lon: 86.0945},
+				{PlaceID: "b", Name: "ମନଦିର", Lat: 19.8876, Lon: 86.0945},
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "same odia name nearby",
+			places: []Place{
+				{PlaceID: "a", Name: "ସୂର୍ଯ୍ୟ ମନ୍ଦିର", Lat: 19.8876, Lon: 86.0945},
+				{PlaceID: "b", Name: "ସୂର୍ଯ୍ୟ-ମନ୍ଦିର", Lat: 19.8876, Lon: 86.0946},
+			},
+			want: []string{"a"},
+		},
+		{
+			name: "unnamed places are not merged",
+			places: []Place{
+				{PlaceID: "a", Lat: 19.8876, Lon: 86.0945},
+				{PlaceID: "b", Lat: 19.8876, Lon: 86.0945},
+			},
+			want: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, p := range arrangePlaces(tt.places, origin, SortRelevance, 0) {
+				got = append(got, p.PlaceID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("arrangePlaces kept %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
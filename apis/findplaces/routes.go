@@ -0,0 +1,21 @@
+package findplaces
+
+import (
+	"net/http"
+
+	"example.com/m/middleware"
+	"github.com/go-chi/chi/v5"
+)
+
+func Routes() http.Handler {
+	r := chi.NewRouter()
+
+	r.Group(func(protected chi.Router) {
+		protected.Use(middleware.Auth)
+
+		protected.Get("/search", SearchHandler)
+		protected.Get("/{id}", PlaceHandler)
+	})
+
+	return r
+}
@@ -0,0 +1,135 @@
+package findplaces
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// searchVector must match the expression indexed by
+// places_search_names_fts_idx. It covers the per-language names as well as
+// the default one.
+const searchVector = `to_tsvector('simple', name || ' ' || place_names_text(names) || ' ' || city || ' ' || state)`
+
+// isWordRune reports whether r belongs inside a word. Marks count: Indic
+// scripts write vowel signs and viramas as combining marks, so without them
+// "मंदिर" would fall apart into single letters.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r)
+}
+
+// prefixQuery turns free text into a tsquery where every word is a prefix,
+// so "sun tem" matches "Sun Temple". Returns "" if nothing searchable is left.
+func prefixQuery(q string) string {
+	words := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !isWordRune(r)
+	})
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// likeEscaper escapes LIKE's wildcards (and its escape character) so user
+// text only ever matches literally.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchCatalog finds places whose name, in any language, matches q by word
+// prefix or, failing that, by trigram similarity. When near is set, results
+// are re-ranked so closer places win among similarly good matches.
+func SearchCatalog(ctx context.Context, conn *pgxpool.Pool, q string, near *Point, limit int) ([]Place, error) {
+	tsq := prefixQuery(q)
+	if tsq == "" {
+		return []Place{}, nil
+	}
+
+	// Pull extra candidates when we're going to re-rank by distance.
+	candidates := limit
+	if near != nil {
+		candidates = limit * 4
+	}
+
+	rows, err := conn.Query(ctx,
+		`SELECT `+placeColumns+`,
+			(CASE WHEN lower(name) LIKE lower($4) || '%'
+				OR EXISTS (SELECT 1 FROM jsonb_each_text(names) n WHERE lower(n.value) LIKE lower($4) || '%')
+				THEN 1 ELSE 0 END)
+			+ GREATEST(similarity(name, $1), similarity(place_names_text(names), $1))
+			+ ts_rank(`+searchVector+`, to_tsquery('simple', $2)) AS score
+		 FROM places
+		 WHERE `+searchVector+` @@ to_tsquery('simple', $2) OR name % $1 OR place_names_text(names) % $1
+		 ORDER BY score DESC
+		 LIMIT $3`,
+		q, tsq, candidates, likeEscaper.Replace(q),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type scored struct {
+		place Place
+		score float64
+	}
+	var results []scored
+	for rows.Next() {
+		var s scored
+		if err := rows.Scan(append(placeDest(&s.place), &s.score)...); err == nil {
+			results = append(results, s)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if near != nil {
+		for i := range results {
+			d := Haversine(near.Lat, near.Lon, results[i].place.Lat, results[i].place.Lon)
+			results[i].place.DistanceMeters = d
+			// Decay the score with distance: half at 50km, a third at 100km.
+			results[i].score /= 1 + d/50000
+		}
+		sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+	}
+
+	places := make([]Place, 0, min(limit, len(results)))
+	for i := 0; i < len(results) && i < limit; i++ {
+		places = append(places, results[i].place)
+	}
+	return places, nil
+}
+
+// mergePlaces appends extra to places, skipping IDs already present, until
+// limit is reached.
+func mergePlaces(places, extra []Place, limit int) []Place {
+	seen := make(map[string]bool, len(places))
+	for _, p := range places {
+		seen[p.PlaceID] = true
+	}
+	for _, p := range extra {
+		if len(places) >= limit {
+			break
+		}
+		if !seen[p.PlaceID] {
+			seen[p.PlaceID] = true
+			places = append(places, p)
+		}
+	}
+	return places
+}
+
+// pointsOfInterest keeps the places that have a category. Autocomplete also
+// returns streets, addresses and localities, which have none; they are fine
+// as suggestions but don't belong in the places catalog.
+func pointsOfInterest(places []Place) []Place {
+	pois := make([]Place, 0, len(places))
+	for _, p := range places {
+		if len(p.Categories) > 0 {
+			pois = append(pois, p)
+		}
+	}
+	return pois
+}
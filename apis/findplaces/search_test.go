@@ -0,0 +1,42 @@
+package findplaces
+
+import "testing"
+
+func TestPrefixQuery(t *testing.T) {
+	tests := []struct {
+		name, q, want string
+	}{
+		{"empty", "", ""},
+		{"punctuation only", " ,.!? ", ""},
+		{"one word", "Konark", "konark:*"},
+		{"several words", "sun  tem", "sun:* & tem:*"},
+		{"punctuation between words", "Sun-Temple, Konark!", "sun:* & temple:* & konark:*"},
+		{"tsquery operators dropped", "a & b | !c", "a:* & b:* & c:*"},
+		{"digits", "NH 16", "nh:* & 16:*"},
+		{"hindi", "सूर्य मंदिर", "सूर्य:* & मंदिर:*"},
+		{"odia", "ସୂର୍ଯ୍ୟ ମନ୍ଦିର", "ସୂର୍ଯ୍ୟ:* & ମନ୍ଦିର:*"},
+		{"mixed scripts", "Konark कोणार्क", "konark:* & कोणार्क:*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := prefixQuery(tt.q); got != tt.want {
+				t.Errorf("prefixQuery(%q) = %q, want %q", tt.q, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLikeEscaper(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"sun temple", "sun temple"},
+		{"100%", `100\%`},
+		{"a_b", `a\_b`},
+		{`back\slash`, `back\\slash`},
+		{"मंदिर", "मंदिर"},
+	}
+	for _, tt := range tests {
+		if got := likeEscaper.Replace(tt.in); got != tt.want {
+			t.Errorf("likeEscaper.Replace(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package findplaces
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// spatialEnabled is set once InitSpatial has found the PostGIS geography
+// columns. Without them, spatial queries fall back to a lat/lon box
+// prefilter in SQL and exact Haversine distances in Go.
+var spatialEnabled atomic.Bool
+
+// InitSpatial enables PostGIS-backed queries if the spatial migration could
+// add the geography columns, which it only does where PostGIS is available.
+func InitSpatial(ctx context.Context, conn *pgxpool.Pool) bool {
+	var columns int
+	err := conn.QueryRow(ctx,
+		`SELECT COUNT(*) FROM information_schema.columns
+		 WHERE table_schema = current_schema() AND table_name IN ('places', 'poi_cache')
+		   AND column_name = 'geog'`,
+	).Scan(&columns)
+	if err != nil || columns < 2 {
+		if err != nil {
+			log.Printf("⚠️  PostGIS check failed, using Go distance fallback: %v", err)
+		} else {
+			log.Println("⚠️  PostGIS unavailable, using Go distance fallback")
+		}
+		spatialEnabled.Store(false)
+		return false
+	}
+
+	log.Println("PostGIS spatial indexing enabled")
+	spatialEnabled.Store(true)
+	return true
+}
+
+// boundingBox returns a lat/lon box that contains the circle, for prefiltering
+// on plain indexed columns.
+func boundingBox(lat, lon, radius float64) BBox {
+	dLat := radius / 111320
+	south, north := math.Max(lat-dLat, -90), math.Min(lat+dLat, 90)
+
+	c := math.Cos(lat * math.Pi / 180)
+	if c < 1e-6 || radius/(111320*c) >= 180 {
+		return BBox{-180, south, 180, north}
+	}
+	dLon := radius / (111320 * c)
+	return BBox{normalizeLon(lon - dLon), south, normalizeLon(lon + dLon), north}
+}
+
+// boxCondition matches latCol/lonCol inside box, wrapping at the antimeridian.
+func boxCondition(box BBox, latCol, lonCol string, args *[]any) string {
+	*args = append(*args, box.South(), box.North(), box.West(), box.East())
+	n := len(*args)
+
+	join := "AND"
+	if box.CrossesAntimeridian() {
+		join = "OR"
+	}
+	return fmt.Sprintf(`%s BETWEEN $%d AND $%d AND (%s >= $%d %s %s <= $%d)`,
+		latCol, n-3, n-2, lonCol, n-1, join, lonCol, n)
+}
+
+// FindNearbyCachedResponse returns the closest cached circle search within
+// maxDistance meters of lat/lon that used the same radius and category.
+func FindNearbyCachedResponse(ctx context.Context, conn *pgxpool.Pool, lat, lon float64,
+	radius int, category string, maxDistance float64) (CachedEntry, bool) {
+
+	args := []any{radius, category}
+	query := `SELECT response, latitude, longitude, created_at FROM poi_cache
+		WHERE area_kind='circle' AND radius=$1 AND category=$2 AND `
+
+	if spatialEnabled.Load() {
+		args = append(args, lon, lat, maxDistance)
+		query += `ST_DWithin(geog, ST_MakePoint($3, $4)::geography, $5)
+			ORDER BY geog <-> ST_MakePoint($3, $4)::geography LIMIT 1`
+	} else {
+		query += boxCondition(boundingBox(lat, lon, maxDistance), "latitude", "longitude", &args)
+	}
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		log.Printf("Nearby cache lookup failed: %v", err)
+		return CachedEntry{}, false
+	}
+	defer rows.Close()
+
+	var best CachedEntry
+	var bestRaw []byte
+	bestDist := math.Inf(1)
+	for rows.Next() {
+		var raw []byte
+		var e CachedEntry
+		if err := rows.Scan(&raw, &e.Lat, &e.Lon, &e.CreatedAt); err != nil {
+			continue
+		}
+		if d := Haversine(lat, lon, e.Lat, e.Lon); d <= maxDistance && d < bestDist {
+			best, bestRaw, bestDist = e, raw, d
+		}
+	}
+	if bestRaw == nil {
+		return CachedEntry{}, false
+	}
+
+	if err := json.Unmarshal(bestRaw, &best.Response); err != nil {
+		return CachedEntry{}, false
+	}
+	return best, true
+}
+
+// PlacesWithin returns catalog places within radius meters of lat/lon,
+// nearest first, with DistanceMeters filled in.
+func PlacesWithin(ctx context.Context, conn *pgxpool.Pool, lat, lon float64, radius float64, limit int) ([]Place, error) {
+	args := []any{}
+	var query string
+
+	if spatialEnabled.Load() {
+		args = append(args, lon, lat, radius, limit)
+		query = `SELECT ` + placeColumns + ` FROM places
+			WHERE ST_DWithin(geog, ST_MakePoint($1, $2)::geography, $3)
+			ORDER BY geog <-> ST_MakePoint($1, $2)::geography LIMIT $4`
+	} else {
+		// Order by flat-earth distance so the cap keeps the nearest places.
+		// It can disagree with Haversine near the cutoff, so take a margin
+		// of extra candidates and trim after the exact sort below.
+		where := boxCondition(boundingBox(lat, lon, radius), "latitude", "longitude", &args)
+		args = append(args, lat, lon, math.Cos(lat*math.Pi/180), 2*limit)
+		n := len(args)
+		query = `SELECT ` + placeColumns + ` FROM places WHERE ` + where + fmt.Sprintf(`
+			ORDER BY power(latitude - $%[1]d, 2)
+				+ power(LEAST(ABS(longitude - $%[2]d), 360 - ABS(longitude - $%[2]d)) * $%[3]d, 2)
+			LIMIT $%[4]d`, n-3, n-2, n-1, n)
+	}
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	places := make([]Place, 0)
+	for rows.Next() {
+		p, err := scanPlace(rows)
+		if err != nil {
+			continue
+		}
+		p.DistanceMeters = Haversine(lat, lon, p.Lat, p.Lon)
+		if p.DistanceMeters <= radius {
+			places = append(places, p)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(places, func(i, j int) bool { return places[i].DistanceMeters < places[j].DistanceMeters })
+	if len(places) > limit {
+		places = places[:limit]
+	}
+	return places, nil
+}
+
+// PlacesInBox returns up to limit catalog places inside box, most reviewed
+// and best rated first, so a box holding more than limit keeps the places
+// people care about. place_id breaks ties so the same data always yields the
+// same subset. truncated reports that the box held more than limit.
+func PlacesInBox(ctx context.Context, conn *pgxpool.Pool, box BBox, limit int) (places []Place, truncated bool, err error) {
+	args := []any{}
+	query := `SELECT ` + placeColumns + ` FROM places WHERE ` +
+		boxCondition(box, "latitude", "longitude", &args)
+	args = append(args, limit+1)
+	query += fmt.Sprintf(` ORDER BY rating_count DESC, rating_avg DESC NULLS LAST, place_id LIMIT $%d`, len(args))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	places = make([]Place, 0)
+	for rows.Next() {
+		if p, err := scanPlace(rows); err == nil {
+			places = append(places, p)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	if len(places) > limit {
+		return places[:limit], true, nil
+	}
+	return places, false, nil
+}
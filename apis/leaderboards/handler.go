@@ -0,0 +1,103 @@
+package leaderboards
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"example.com/m/i18n"
+	"example.com/m/utils"
+)
+
+type LeaderboardResponse struct {
+	Metric  string  `json:"metric"`
+	Window  string  `json:"window"`
+	Period  string  `json:"period"`
+	Scope   string  `json:"scope"`
+	Region  string  `json:"region,omitempty"`
+	Entries []Entry `json:"entries"`
+	// Me is the caller's own entry, even when outside the top entries; null
+	// until they've scored.
+	Me *Entry `json:"me"`
+}
+
+// LeaderboardHandler ranks users
+// @Summary Leaderboard
+// @Description Users ranked by XP, distinct places visited (verified, counted on the first visit) or badges over this week, this month (UTC) or all time. Scope global, region (users whose most-visited state is region, default the caller's) or friends (the caller and users they added). The caller's own rank is always included.
+// @Tags leaderboards
+// @Produce json
+// @Security BearerAuth
+// @Param metric query string false "xp (default), visits or badges"
+// @Param window query string false "weekly, monthly or all (default)"
+// @Param scope query string false "global (default), region or friends"
+// @Param region query string false "State to rank, for scope=region"
+// @Param limit query int false "Top entries to return (default 20, max 100)"
+// @Success 200 {object} LeaderboardResponse
+// @Failure 400 {object} map[string]string "Invalid parameters"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/leaderboard [get]
+func LeaderboardHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	res := LeaderboardResponse{
+		Metric: query.Get("metric"),
+		Window: query.Get("window"),
+		Scope:  query.Get("scope"),
+		Region: query.Get("region"),
+	}
+	if res.Metric == "" {
+		res.Metric = MetricXP
+	}
+	if res.Window == "" {
+		res.Window = WindowAll
+	}
+	if res.Scope == "" {
+		res.Scope = ScopeGlobal
+	}
+	if (res.Metric != MetricXP && res.Metric != MetricVisits && res.Metric != MetricBadges) ||
+		(res.Window != WindowWeekly && res.Window != WindowMonthly && res.Window != WindowAll) ||
+		(res.Scope != ScopeGlobal && res.Scope != ScopeRegion && res.Scope != ScopeFriends) {
+		i18n.Error(w, r, i18n.InvalidLeaderboard, http.StatusBadRequest)
+		return
+	}
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 || limit > 100 {
+		limit = 20
+	}
+
+	if res.Scope != ScopeRegion {
+		res.Region = ""
+	} else if res.Region == "" {
+		res.Region, err = HomeRegion(r.Context(), userUUID)
+		if errors.Is(err, ErrNoRegion) {
+			i18n.Error(w, r, i18n.NoHomeRegion, http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			log.Printf("Home region lookup failed: %v", err)
+			i18n.Error(w, r, i18n.LeaderboardFailed, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	res.Period = period(res.Window, time.Now())
+	res.Entries, res.Me, err = Board(r.Context(), userUUID, res.Metric, res.Period, res.Scope, res.Region, limit)
+	if err != nil {
+		log.Printf("Leaderboard query failed: %v", err)
+		i18n.Error(w, r, i18n.LeaderboardFailed, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,185 @@
+package leaderboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"example.com/m/db"
+	"example.com/m/events"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	MetricXP     = "xp"
+	MetricVisits = "visits"
+	MetricBadges = "badges"
+
+	WindowWeekly  = "weekly"
+	WindowMonthly = "monthly"
+	WindowAll     = "all"
+
+	ScopeGlobal  = "global"
+	ScopeRegion  = "region"
+	ScopeFriends = "friends"
+)
+
+var ErrNoRegion = errors.New("no region to rank in")
+
+// Init keeps the running totals current.
+func Init() {
+	events.Subscribe(events.XPGranted, func(ctx context.Context, e events.Event) {
+		add(ctx, MetricXP, e.UserUUID, e.Points, e.At)
+	})
+	events.Subscribe(events.BadgeAwarded, func(ctx context.Context, e events.Event) {
+		add(ctx, MetricBadges, e.UserUUID, 1, e.At)
+	})
+	events.Subscribe(events.Visit, onVisit)
+}
+
+// period names the leaderboard window containing t, in UTC.
+func period(window string, t time.Time) string {
+	t = t.UTC()
+	switch window {
+	case WindowWeekly:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("w:%04d-W%02d", year, week)
+	case WindowMonthly:
+		return t.Format("m:2006-01")
+	default:
+		return "all"
+	}
+}
+
+// add bumps the user's score in every window containing at.
+func add(ctx context.Context, metric, userUUID string, delta int, at time.Time) {
+	periods := []string{period(WindowAll, at), period(WindowWeekly, at), period(WindowMonthly, at)}
+	_, err := db.Conn.Exec(ctx,
+		`INSERT INTO leaderboard_scores (metric, period, user_uuid, score)
+		 SELECT $1, p, $3, $4 FROM unnest($2::text[]) p
+		 ON CONFLICT (metric, period, user_uuid) DO UPDATE
+		 SET score = leaderboard_scores.score + EXCLUDED.score, updated_at = NOW()`,
+		metric, periods, userUUID, delta,
+	)
+	if err != nil {
+		log.Printf("Leaderboard %s update failed: %v", metric, err)
+	}
+}
+
+// onVisit counts the distinct places a user has verified visits to, and
+// credits each place's state to the user's regions once. Repeat visits to a
+// place change neither. Whether a visit is the first is decided by claiming
+// the user_place_firsts row, so of two visits handled at once exactly one
+// counts.
+func onVisit(ctx context.Context, e events.Event) {
+	if !e.Verified {
+		return
+	}
+
+	tag, err := db.Conn.Exec(ctx,
+		`INSERT INTO user_place_firsts (user_uuid, place_id, visited_at) VALUES ($1, $2, $3)
+		 ON CONFLICT (user_uuid, place_id) DO NOTHING`,
+		e.UserUUID, e.PlaceID, e.At,
+	)
+	if err != nil {
+		log.Printf("Leaderboard visit check failed: %v", err)
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		return
+	}
+	add(ctx, MetricVisits, e.UserUUID, 1, e.At)
+
+	_, err = db.Conn.Exec(ctx,
+		`INSERT INTO user_regions (user_uuid, region, visits)
+		 SELECT $1, state, 1 FROM places WHERE place_id=$2 AND state <> ''
+		 ON CONFLICT (user_uuid, region) DO UPDATE SET visits = user_regions.visits + 1`,
+		e.UserUUID, e.PlaceID,
+	)
+	if err != nil {
+		log.Printf("User region update failed: %v", err)
+	}
+}
+
+// HomeRegion returns the state where the user has verified visits to the
+// most places.
+func HomeRegion(ctx context.Context, userUUID string) (string, error) {
+	var region string
+	err := db.Conn.QueryRow(ctx,
+		`SELECT region FROM user_regions WHERE user_uuid=$1
+		 ORDER BY visits DESC, region LIMIT 1`,
+		userUUID,
+	).Scan(&region)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrNoRegion
+	}
+	return region, err
+}
+
+type Entry struct {
+	Rank      int     `json:"rank"`
+	UserUUID  string  `json:"user_uuid"`
+	Name      string  `json:"name"`
+	AvatarURL *string `json:"avatar_url,omitempty"`
+	Score     int64   `json:"score"`
+}
+
+// Board ranks users for metric over period, returning the top limit and the
+// caller's own entry (nil if they haven't scored). region limits it to users
+// whose home region that is; friends limits it to the caller and the people
+// they've added.
+func Board(ctx context.Context, userUUID, metric, periodKey, scope, region string, limit int) ([]Entry, *Entry, error) {
+	args := []any{metric, periodKey, userUUID, limit}
+	join := ``
+	conds := []string{`s.metric = $1`, `s.period = $2`, `s.score > 0`}
+	switch scope {
+	case ScopeRegion:
+		args = append(args, region)
+		join = ` JOIN (
+			SELECT DISTINCT ON (user_uuid) user_uuid, region FROM user_regions
+			ORDER BY user_uuid, visits DESC, region
+		) home ON home.user_uuid = s.user_uuid AND home.region = $5`
+	case ScopeFriends:
+		conds = append(conds, `(s.user_uuid = $3 OR s.user_uuid IN (
+			SELECT friend_uuid FROM user_friends WHERE user_uuid = $3))`)
+	}
+
+	rows, err := db.Conn.Query(ctx,
+		`WITH board AS (
+			SELECT s.user_uuid, s.score,
+				RANK() OVER (ORDER BY s.score DESC) AS rank,
+				ROW_NUMBER() OVER (ORDER BY s.score DESC, s.user_uuid) AS position
+			FROM leaderboard_scores s`+join+`
+			WHERE `+strings.Join(conds, " AND ")+`
+		)
+		SELECT b.rank, b.user_uuid::text, COALESCE(u.name, ''), u.avatar_url, b.score
+		FROM board b LEFT JOIN users u ON u.uuid = b.user_uuid
+		WHERE b.position <= $4 OR b.user_uuid = $3
+		ORDER BY b.position`,
+		args...,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	entries := []Entry{}
+	var me *Entry
+	for rows.Next() {
+		var e Entry
+		if err := rows.Scan(&e.Rank, &e.UserUUID, &e.Name, &e.AvatarURL, &e.Score); err != nil {
+			return nil, nil, err
+		}
+		if e.UserUUID == userUUID {
+			mine := e
+			me = &mine
+		}
+		if len(entries) < limit {
+			entries = append(entries, e)
+		}
+	}
+	return entries, me, rows.Err()
+}
@@ -0,0 +1,73 @@
+package leaderboards
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example.com/m/events"
+	"example.com/m/middleware"
+)
+
+func TestPeriod(t *testing.T) {
+	ist := time.FixedZone("IST", 5*3600+1800)
+	tests := []struct {
+		window string
+		at     time.Time
+		want   string
+	}{
+		{WindowAll, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), "all"},
+		{"", time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), "all"},
+		{WindowWeekly, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), "w:2026-W43"},
+		{WindowMonthly, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), "m:2026-10"},
+		// Weeks start on Monday: Sunday 2026-10-25 is still week 43.
+		{WindowWeekly, time.Date(2026, 10, 25, 23, 59, 59, 0, time.UTC), "w:2026-W43"},
+		{WindowWeekly, time.Date(2026, 10, 26, 0, 0, 0, 0, time.UTC), "w:2026-W44"},
+		// ISO weeks cross years: 2027-01-01 is in the last week of 2026,
+		// and 2024-12-30 in the first week of 2025.
+		{WindowWeekly, time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC), "w:2026-W53"},
+		{WindowWeekly, time.Date(2024, 12, 30, 12, 0, 0, 0, time.UTC), "w:2025-W01"},
+		{WindowMonthly, time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC), "m:2027-01"},
+		// Periods are taken in UTC: 03:00 on Nov 1 in India is still
+		// October in UTC.
+		{WindowMonthly, time.Date(2026, 11, 1, 3, 0, 0, 0, ist), "m:2026-10"},
+		{WindowWeekly, time.Date(2026, 10, 26, 3, 0, 0, 0, ist), "w:2026-W43"},
+	}
+	for _, tt := range tests {
+		if got := period(tt.window, tt.at); got != tt.want {
+			t.Errorf("period(%q, %v) = %q, want %q", tt.window, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestOnVisitIgnoresUnverified(t *testing.T) {
+	// db.Conn is nil here, so touching the database would panic.
+	onVisit(context.Background(), events.Event{Kind: events.Visit, UserUUID: "u", PlaceID: "p", Verified: false})
+}
+
+func TestLeaderboardHandlerRejectsBadParameters(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"unknown metric", "metric=reviews"},
+		{"unknown window", "window=daily"},
+		{"unknown scope", "scope=city"},
+		{"metric case", "metric=XP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/leaderboard?"+tt.query, nil)
+			r = r.WithContext(context.WithValue(r.Context(), middleware.UserUUIDKey, "u"))
+			w := httptest.NewRecorder()
+
+			LeaderboardHandler(w, r)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package offline
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"example.com/m/i18n"
+)
+
+type RegionsResponse struct {
+	Regions []Region `json:"regions"`
+}
+
+// RegionsHandler lists the named regions packs can be built for
+// @Summary List Offline Regions
+// @Description Named regions available as offline packs
+// @Tags offline
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} RegionsResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /offline/regions [get]
+func RegionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RegionsResponse{Regions: namedRegions})
+}
+
+// PackHandler builds an offline region pack
+// @Summary Download Offline Region Pack
+// @Description Gzipped JSON bundle of every catalog place in a named region or bounding box, with the metadata of their images for the app to cache. Pass since=<version you have> to get only what changed; 304 if you're up to date. Bounding boxes are widened to a 0.25 degree grid. Regions with more than 20000 places are refused; ask for smaller bounding boxes.
+// @Tags offline
+// @Produce application/gzip
+// @Security BearerAuth
+// @Param region query string false "Named region key (see /offline/regions)"
+// @Param bbox query string false "west,south,east,north (instead of region)"
+// @Param since query int false "Version already downloaded, for a delta pack"
+// @Success 200 {object} Pack "gzip-compressed"
+// @Success 304 "Already up to date"
+// @Failure 400 {object} map[string]string "Invalid region"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Unknown region"
+// @Failure 413 {object} map[string]string "Region too large"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /offline/packs [get]
+func PackHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var region Region
+	switch {
+	case query.Get("region") != "" && query.Get("bbox") != "":
+		i18n.Error(w, r, i18n.RegionOrBBox, http.StatusBadRequest)
+		return
+	case query.Get("region") != "":
+		var ok bool
+		if region, ok = namedRegion(query.Get("region")); !ok {
+			i18n.Error(w, r, i18n.UnknownRegion, http.StatusNotFound)
+			return
+		}
+	case query.Get("bbox") != "":
+		var err error
+		if region, err = bboxRegion(query.Get("bbox")); err != nil {
+			i18n.Error(w, r, i18n.InvalidBBox, http.StatusBadRequest)
+			return
+		}
+	default:
+		i18n.Error(w, r, i18n.RegionRequired, http.StatusBadRequest)
+		return
+	}
+
+	since := 0
+	if v := query.Get("since"); v != "" {
+		var err error
+		if since, err = strconv.Atoi(v); err != nil || since < 0 {
+			i18n.Error(w, r, i18n.InvalidSince, http.StatusBadRequest)
+			return
+		}
+	}
+
+	pack, err := BuildPack(r.Context(), region, since)
+	if err != nil {
+		log.Printf("Region pack build failed for %s: %v", region.Key, err)
+		if errors.Is(err, ErrRegionTooLarge) {
+			i18n.Error(w, r, i18n.RegionTooLarge, http.StatusRequestEntityTooLarge)
+			return
+		}
+		if errors.Is(err, errConcurrentUpdate) {
+			w.Header().Set("Retry-After", "1")
+			i18n.Error(w, r, i18n.PackBusy, http.StatusServiceUnavailable)
+			return
+		}
+		i18n.Error(w, r, i18n.PackFailed, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("X-Pack-Version", strconv.Itoa(pack.Version))
+	if since == pack.Version {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	pack.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
+
+	name := strings.NewReplacer(":", "_", ",", "_").Replace(region.Key)
+	filename := fmt.Sprintf("%s-v%d.json.gz", name, pack.Version)
+	if !pack.Full {
+		filename = fmt.Sprintf("%s-v%d-from-v%d.json.gz", name, pack.Version, pack.BaseVersion)
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	gz := gzip.NewWriter(w)
+	if err := json.NewEncoder(gz).Encode(pack); err != nil {
+		log.Printf("Writing region pack failed: %v", err)
+	}
+	gz.Close()
+}
@@ -0,0 +1,214 @@
+package offline
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"example.com/m/apis/findplaces"
+	"example.com/m/db"
+	"github.com/jackc/pgx/v5"
+)
+
+// PackFormat is bumped whenever the bundle layout changes incompatibly.
+const PackFormat = 1
+
+// keepVersions is how many versions per region we can still diff against.
+const keepVersions = 10
+
+const maxPlacesPerPack = 20000
+
+var (
+	errConcurrentUpdate = errors.New("region pack changed while building, retry")
+	ErrRegionTooLarge   = errors.New("region holds too many places for one pack")
+)
+
+// Pack is the bundle the app stores for offline use. A delta pack (Full
+// false) holds only places added or changed since BaseVersion, plus the IDs
+// of places that are gone.
+type Pack struct {
+	Format      int                `json:"format"`
+	Region      Region             `json:"region"`
+	Version     int                `json:"version"`
+	BaseVersion int                `json:"base_version,omitempty"`
+	Full        bool               `json:"full"`
+	GeneratedAt string             `json:"generated_at"`
+	Places      []findplaces.Place `json:"places"`
+	Removed     []string           `json:"removed,omitempty"`
+	// Images lists the pictures of the pack's places for the app to fetch
+	// and cache; the images themselves aren't in the bundle.
+	Images []PackImage `json:"images"`
+}
+
+type PackImage struct {
+	PlaceID string `json:"place_id"`
+	findplaces.Image
+}
+
+// packImages lists the images of places.
+func packImages(places []findplaces.Place) []PackImage {
+	images := make([]PackImage, 0)
+	for _, p := range places {
+		for _, img := range p.Images {
+			images = append(images, PackImage{PlaceID: p.PlaceID, Image: img})
+		}
+	}
+	return images
+}
+
+type packVersion struct {
+	Version     int
+	ContentHash string
+	PlaceHashes map[string]string
+}
+
+// hashPlaces returns a content hash per place and one for the whole set.
+// Only what the app uses offline is hashed: ratings change with every
+// review, and a new version for each would make deltas no smaller than full
+// packs. Packs carry ratings as of when they were built.
+func hashPlaces(places []findplaces.Place) (map[string]string, string) {
+	hashes := make(map[string]string, len(places))
+	for _, p := range places {
+		p.Rating, p.RatingCount, p.DistanceMeters = nil, 0, 0
+		raw, _ := json.Marshal(p)
+		sum := sha256.Sum256(raw)
+		hashes[p.PlaceID] = hex.EncodeToString(sum[:12])
+	}
+
+	ids := make([]string, 0, len(hashes))
+	for id := range hashes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	h := sha256.New()
+	for _, id := range ids {
+		h.Write([]byte(id + "=" + hashes[id] + ";"))
+	}
+	return hashes, hex.EncodeToString(h.Sum(nil))
+}
+
+func getVersion(ctx context.Context, regionKey string, version int) (packVersion, error) {
+	var v packVersion
+	var raw []byte
+	var err error
+	if version > 0 {
+		err = db.Conn.QueryRow(ctx,
+			`SELECT version, content_hash, place_hashes FROM region_packs
+			 WHERE region_key=$1 AND version=$2`,
+			regionKey, version,
+		).Scan(&v.Version, &v.ContentHash, &raw)
+	} else {
+		err = db.Conn.QueryRow(ctx,
+			`SELECT version, content_hash, place_hashes FROM region_packs
+			 WHERE region_key=$1 ORDER BY version DESC LIMIT 1`,
+			regionKey,
+		).Scan(&v.Version, &v.ContentHash, &raw)
+	}
+	if err != nil {
+		return packVersion{}, err
+	}
+
+	err = json.Unmarshal(raw, &v.PlaceHashes)
+	return v, err
+}
+
+// currentVersion returns the version describing exactly these places,
+// publishing a new one if the region's content changed since the last.
+func currentVersion(ctx context.Context, regionKey string, hashes map[string]string, contentHash string) (packVersion, error) {
+	latest, err := getVersion(ctx, regionKey, 0)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return packVersion{}, err
+	}
+	if err == nil && latest.ContentHash == contentHash {
+		return latest, nil
+	}
+
+	next := packVersion{Version: latest.Version + 1, ContentHash: contentHash, PlaceHashes: hashes}
+	raw, _ := json.Marshal(hashes)
+
+	tag, err := db.Conn.Exec(ctx,
+		`INSERT INTO region_packs(region_key, version, content_hash, place_hashes, place_count)
+		 VALUES($1, $2, $3, $4, $5) ON CONFLICT DO NOTHING`,
+		regionKey, next.Version, contentHash, raw, len(hashes),
+	)
+	if err != nil {
+		return packVersion{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		// Someone else published this version number first. Fine if it has
+		// our content; otherwise the region changed under us.
+		other, err := getVersion(ctx, regionKey, next.Version)
+		if err != nil {
+			return packVersion{}, err
+		}
+		if other.ContentHash != contentHash {
+			return packVersion{}, errConcurrentUpdate
+		}
+		return other, nil
+	}
+
+	_, err = db.Conn.Exec(ctx,
+		`DELETE FROM region_packs WHERE region_key=$1 AND version <= $2`,
+		regionKey, next.Version-keepVersions,
+	)
+	return next, err
+}
+
+// BuildPack assembles the pack for region. If since names a version we still
+// have, only the difference from it is included.
+func BuildPack(ctx context.Context, region Region, since int) (Pack, error) {
+	// A pack cut off at the cap would drop different places as the region
+	// changes, so versions and deltas wouldn't describe the region at all.
+	places, truncated, err := findplaces.PlacesInBox(ctx, db.Conn, region.BBox, maxPlacesPerPack)
+	if err != nil {
+		return Pack{}, err
+	}
+	if truncated {
+		return Pack{}, ErrRegionTooLarge
+	}
+
+	hashes, contentHash := hashPlaces(places)
+	current, err := currentVersion(ctx, region.Key, hashes, contentHash)
+	if err != nil {
+		return Pack{}, err
+	}
+
+	pack := Pack{Format: PackFormat, Region: region, Version: current.Version, Full: true,
+		Places: places, Images: packImages(places)}
+	if since <= 0 || since >= current.Version {
+		return pack, nil
+	}
+
+	base, err := getVersion(ctx, region.Key, since)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return pack, nil // too old to diff against; send everything
+	}
+	if err != nil {
+		return Pack{}, err
+	}
+
+	changed := make([]findplaces.Place, 0)
+	for _, p := range places {
+		if base.PlaceHashes[p.PlaceID] != hashes[p.PlaceID] {
+			changed = append(changed, p)
+		}
+	}
+	var removed []string
+	for id := range base.PlaceHashes {
+		if _, ok := hashes[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(removed)
+
+	pack.Full = false
+	pack.BaseVersion = base.Version
+	pack.Places = changed
+	pack.Images = packImages(changed)
+	pack.Removed = removed
+	return pack, nil
+}
@@ -0,0 +1,55 @@
+package offline
+
+import (
+	"slices"
+	"testing"
+
+	"example.com/m/apis/findplaces"
+)
+
+func TestHashPlaces(t *testing.T) {
+	rating := func(v float64) *float64 { return &v }
+	base := []findplaces.Place{
+		{PlaceID: "konark", Name: "Konark Sun Temple", Lat: 19.8876, Lon: 86.0945, City: "Konark"},
+		{PlaceID: "puri", Name: "Jagannath Temple", Lat: 19.8048, Lon: 85.8179, City: "Puri"},
+	}
+	with := func(f func(p []findplaces.Place)) []findplaces.Place {
+		p := append([]findplaces.Place(nil), base...)
+		f(p)
+		return p
+	}
+	baseHashes, baseContent := hashPlaces(base)
+
+	tests := []struct {
+		name        string
+		places      []findplaces.Place
+		sameContent bool
+		changed     []string
+	}{
+		{"identical", with(func(p []findplaces.Place) {}), true, nil},
+		{"other order", []findplaces.Place{base[1], base[0]}, true, nil},
+		{"new rating", with(func(p []findplaces.Place) { p[0].Rating, p[0].RatingCount = rating(4.5), 12 }), true, nil},
+		{"distance", with(func(p []findplaces.Place) { p[1].DistanceMeters = 1200 }), true, nil},
+		{"renamed", with(func(p []findplaces.Place) { p[0].Name = "Konark" }), false, []string{"konark"}},
+		{"moved", with(func(p []findplaces.Place) { p[1].Lat += 0.001 }), false, []string{"puri"}},
+		{"new image", with(func(p []findplaces.Place) { p[1].Images = []findplaces.Image{{URL: "https://example.com/puri.jpg"}} }), false, []string{"puri"}},
+		{"one fewer", base[:1], false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hashes, content := hashPlaces(tt.places)
+			if (content == baseContent) != tt.sameContent {
+				t.Errorf("content hash same = %v, want %v", content == baseContent, tt.sameContent)
+			}
+			var changed []string
+			for _, p := range base {
+				if h, ok := hashes[p.PlaceID]; ok && h != baseHashes[p.PlaceID] {
+					changed = append(changed, p.PlaceID)
+				}
+			}
+			if !slices.Equal(changed, tt.changed) {
+				t.Errorf("changed places = %v, want %v", changed, tt.changed)
+			}
+		})
+	}
+}
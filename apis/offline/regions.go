@@ -0,0 +1,83 @@
+package offline
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"example.com/m/apis/findplaces"
+)
+
+// Region is an area a pack can be built for.
+type Region struct {
+	Key  string          `json:"key"`
+	Name string          `json:"name"`
+	BBox findplaces.BBox `json:"bbox" swaggertype:"array,number"`
+}
+
+// namedRegions are the regions offered in the app's download list.
+var namedRegions = []Region{
+	{Key: "odisha", Name: "Odisha", BBox: findplaces.BBox{81.38, 17.78, 87.49, 22.57}},
+	{Key: "bhubaneswar", Name: "Bhubaneswar", BBox: findplaces.BBox{85.72, 20.21, 85.92, 20.38}},
+	{Key: "puri", Name: "Puri", BBox: findplaces.BBox{85.77, 19.77, 85.90, 19.84}},
+	{Key: "konark", Name: "Konark", BBox: findplaces.BBox{86.05, 19.86, 86.13, 19.92}},
+}
+
+func namedRegion(key string) (Region, bool) {
+	for _, r := range namedRegions {
+		if r.Key == key {
+			return r, true
+		}
+	}
+	return Region{}, false
+}
+
+// maxPackDegrees bounds ad-hoc regions to roughly a state. The InvalidBBox
+// message quotes it.
+const maxPackDegrees = 10
+
+// packGridDegrees is the grid ad-hoc regions are snapped to, about 28km at
+// the equator.
+const packGridDegrees = 0.25
+
+// bboxRegion parses "west,south,east,north" into an ad-hoc region. The box is
+// widened to the packGridDegrees grid so every box that rounds to the same
+// cells shares one key and one line of versions, however the app's map
+// happened to be panned.
+func bboxRegion(raw string) (Region, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return Region{}, fmt.Errorf("bbox must be west,south,east,north")
+	}
+
+	var box findplaces.BBox
+	for i, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return Region{}, fmt.Errorf("bbox must be west,south,east,north")
+		}
+		box[i] = v
+	}
+	if err := box.Validate(); err != nil {
+		return Region{}, err
+	}
+	// Check the size before snapping: widening a box that nearly wraps the
+	// world could make it stop crossing the antimeridian.
+	width := box.East() - box.West()
+	if box.CrossesAntimeridian() {
+		width += 360
+	}
+	if width > maxPackDegrees || box.North()-box.South() > maxPackDegrees {
+		return Region{}, fmt.Errorf("bbox may span at most %d degrees", maxPackDegrees)
+	}
+
+	box = findplaces.BBox{
+		math.Floor(box.West()/packGridDegrees) * packGridDegrees,
+		math.Floor(box.South()/packGridDegrees) * packGridDegrees,
+		math.Ceil(box.East()/packGridDegrees) * packGridDegrees,
+		math.Ceil(box.North()/packGridDegrees) * packGridDegrees,
+	}
+	key := fmt.Sprintf("bbox:%.2f,%.2f,%.2f,%.2f", box.West(), box.South(), box.East(), box.North())
+	return Region{Key: key, Name: "Custom area", BBox: box}, nil
+}
@@ -0,0 +1,49 @@
+package offline
+
+import (
+	"testing"
+
+	"example.com/m/apis/findplaces"
+)
+
+func TestBBoxRegion(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    findplaces.BBox
+		wantKey string
+		wantErr bool
+	}{
+		{"on the grid", "85.75,20.25,86,20.5", findplaces.BBox{85.75, 20.25, 86, 20.5}, "bbox:85.75,20.25,86.00,20.50", false},
+		{"widened outward", "85.8,20.3,85.9,20.4", findplaces.BBox{85.75, 20.25, 86, 20.5}, "bbox:85.75,20.25,86.00,20.50", false},
+		{"same cells, other pan", "85.76,20.26,85.99,20.49", findplaces.BBox{85.75, 20.25, 86, 20.5}, "bbox:85.75,20.25,86.00,20.50", false},
+		{"spaces", " 85.8, 20.3 ,85.9,20.4", findplaces.BBox{85.75, 20.25, 86, 20.5}, "bbox:85.75,20.25,86.00,20.50", false},
+		{"southern and western", "-74.1,-33.9,-73.9,-33.8", findplaces.BBox{-74.25, -34, -73.75, -33.75}, "bbox:-74.25,-34.00,-73.75,-33.75", false},
+		{"across the antimeridian", "179.9,-17,-179.9,-16.9", findplaces.BBox{179.75, -17, -179.75, -16.75}, "bbox:179.75,-17.00,-179.75,-16.75", false},
+		{"at the edges", "-180,-90,-179.9,-89.9", findplaces.BBox{-180, -90, -179.75, -89.75}, "bbox:-180.00,-90.00,-179.75,-89.75", false},
+		{"largest", "80,15,90,25", findplaces.BBox{80, 15, 90, 25}, "bbox:80.00,15.00,90.00,25.00", false},
+		{"too wide", "80,15,90.1,25", findplaces.BBox{}, "", true},
+		{"too tall", "80,15,81,25.1", findplaces.BBox{}, "", true},
+		// Snapped naively this would stop crossing and look tiny.
+		{"nearly the whole world", "10.2,0,10.1,1", findplaces.BBox{}, "", true},
+		{"three values", "85,20,86", findplaces.BBox{}, "", true},
+		{"not a number", "85,20,86,north", findplaces.BBox{}, "", true},
+		{"out of range", "85,20,86,91", findplaces.BBox{}, "", true},
+		{"upside down", "85,21,86,20", findplaces.BBox{}, "", true},
+		{"no width", "85,20,85,21", findplaces.BBox{}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region, err := bboxRegion(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("bboxRegion(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if region.BBox != tt.want || region.Key != tt.wantKey {
+				t.Errorf("bboxRegion(%q) = %v %q, want %v %q", tt.raw, region.BBox, region.Key, tt.want, tt.wantKey)
+			}
+		})
+	}
+}
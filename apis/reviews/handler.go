@@ -0,0 +1,261 @@
+package reviews
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	"example.com/m/apis/findplaces"
+	"example.com/m/i18n"
+	"example.com/m/pagination"
+	"example.com/m/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+type ReviewRequest struct {
+	Rating int    `json:"rating" example:"4"`
+	Body   string `json:"body"`
+}
+
+type ReviewsResponse struct {
+	// Rating and RatingCount summarise every review of the place, whatever
+	// the page shows.
+	Rating      *float64 `json:"rating,omitempty"`
+	RatingCount int      `json:"rating_count"`
+	Reviews     []Review `json:"reviews"`
+	NextCursor  string   `json:"next_cursor,omitempty"`
+}
+
+type HelpfulResponse struct {
+	HelpfulCount int  `json:"helpful_count"`
+	Helpful      bool `json:"helpful"`
+}
+
+func reviewError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, findplaces.ErrPlaceNotFound):
+		i18n.Error(w, r, i18n.PlaceNotFound, http.StatusNotFound)
+	case errors.Is(err, ErrReviewNotFound):
+		i18n.Error(w, r, i18n.ReviewNotFound, http.StatusNotFound)
+	case errors.Is(err, ErrNotVisited):
+		i18n.Error(w, r, i18n.NotVisited, http.StatusForbidden)
+	case errors.Is(err, ErrNotVerified):
+		i18n.Error(w, r, i18n.VisitNotVerified, http.StatusForbidden)
+	case errors.Is(err, ErrOwnReview):
+		i18n.Error(w, r, i18n.OwnReview, http.StatusBadRequest)
+	default:
+		log.Printf("Review update failed: %v", err)
+		i18n.Error(w, r, i18n.ReviewFailed, http.StatusInternalServerError)
+	}
+}
+
+// ListHandler lists a place's reviews
+// @Summary List Reviews
+// @Description A place's reviews, newest first by default, with its average rating and review count
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param place_id path string true "Place ID"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param order query string false "Sort by time: desc (default) or asc"
+// @Param verified_only query bool false "Only reviews by users with a verified visit"
+// @Success 200 {object} ReviewsResponse
+// @Failure 400 {object} map[string]string "Invalid pagination parameters"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/places/{place_id}/reviews [get]
+func ListHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		i18n.Error(w, r, i18n.InvalidPagination, http.StatusBadRequest)
+		return
+	}
+	verifiedOnly, _ := strconv.ParseBool(r.URL.Query().Get("verified_only"))
+	placeID := chi.URLParam(r, "place_id")
+
+	var res ReviewsResponse
+	res.Reviews, res.NextCursor, err = ListReviews(r.Context(), userUUID, placeID, page, verifiedOnly)
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		i18n.Error(w, r, i18n.InvalidPagination, http.StatusBadRequest)
+		return
+	}
+	if err == nil {
+		res.Rating, res.RatingCount, err = PlaceRating(r.Context(), placeID)
+	}
+	if err != nil {
+		log.Printf("Listing reviews failed: %v", err)
+		i18n.Error(w, r, i18n.ReviewFailed, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// GetHandler returns the user's review of a place
+// @Summary Get My Review
+// @Description The caller's own review of a place
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param place_id path string true "Place ID"
+// @Success 200 {object} Review
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/places/{place_id}/review [get]
+func GetHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	rv, err := UserReview(r.Context(), userUUID, chi.URLParam(r, "place_id"))
+	if err != nil {
+		reviewError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rv)
+}
+
+// SaveHandler writes or edits the user's review of a place
+// @Summary Write Review
+// @Description Rate (1-5) and optionally review a place the caller has logged a visit to, or replace their earlier review. When REVIEWS_REQUIRE_VERIFIED_VISIT is set the visit must be verified. The first review of each place earns XP.
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param place_id path string true "Place ID"
+// @Param request body ReviewRequest true "Review"
+// @Success 200 {object} Review "Review updated"
+// @Success 201 {object} Review "Review created"
+// @Failure 400 {object} map[string]string "Invalid rating or body"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Place not visited, or visit not verified"
+// @Failure 404 {object} map[string]string "Place not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/places/{place_id}/review [put]
+func SaveHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	var req ReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil ||
+		req.Rating < 1 || req.Rating > 5 || utf8.RuneCountInString(req.Body) > maxBody {
+		i18n.Error(w, r, i18n.InvalidReview, http.StatusBadRequest)
+		return
+	}
+
+	rv, created, err := SaveReview(r.Context(), userUUID, chi.URLParam(r, "place_id"), req.Rating, req.Body)
+	if err != nil {
+		reviewError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(rv)
+}
+
+// DeleteHandler deletes the user's review of a place
+// @Summary Delete Review
+// @Description Delete the caller's review of a place
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param place_id path string true "Place ID"
+// @Success 200 {object} map[string]string "status: deleted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/places/{place_id}/review [delete]
+func DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	if err := DeleteReview(r.Context(), userUUID, chi.URLParam(r, "place_id")); err != nil {
+		reviewError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+}
+
+// vote marks the review in the path helpful for the caller, or unmarks it.
+func vote(w http.ResponseWriter, r *http.Request, helpful bool) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		i18n.Error(w, r, i18n.ReviewNotFound, http.StatusNotFound)
+		return
+	}
+
+	count, err := Vote(r.Context(), userUUID, id, helpful)
+	if err != nil {
+		reviewError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(HelpfulResponse{HelpfulCount: count, Helpful: helpful})
+}
+
+// HelpfulHandler marks a review helpful
+// @Summary Mark Review Helpful
+// @Description Vote a review helpful. Voting twice counts once; authors can't vote on their own reviews.
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Success 200 {object} HelpfulResponse
+// @Failure 400 {object} map[string]string "Own review"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/reviews/{id}/helpful [post]
+func HelpfulHandler(w http.ResponseWriter, r *http.Request) {
+	vote(w, r, true)
+}
+
+// UnhelpfulHandler takes back a helpful vote
+// @Summary Unmark Review Helpful
+// @Description Withdraw the caller's helpful vote on a review
+// @Tags reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Review ID"
+// @Success 200 {object} HelpfulResponse
+// @Failure 400 {object} map[string]string "Own review"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/reviews/{id}/helpful [delete]
+func UnhelpfulHandler(w http.ResponseWriter, r *http.Request) {
+	vote(w, r, false)
+}
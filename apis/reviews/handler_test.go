@@ -0,0 +1,113 @@
+package reviews
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/m/apis/findplaces"
+	"example.com/m/middleware"
+	"example.com/m/pagination"
+	"github.com/go-chi/chi/v5"
+)
+
+// request builds a request from a signed-in user with the given chi URL
+// parameters.
+func request(method, target, body string, params map[string]string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	for k, v := range params {
+		rctx.URLParams.Add(k, v)
+	}
+	ctx := context.WithValue(r.Context(), chi.RouteCtxKey, rctx)
+	return r.WithContext(context.WithValue(ctx, middleware.UserUUIDKey, "u"))
+}
+
+func TestSaveHandlerRejectsInvalidReviews(t *testing.T) {
+	tooLong := strings.Repeat("म", maxBody+1)
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not json", `{"rating":`},
+		{"no rating", `{"body":"Lovely"}`},
+		{"rating too low", `{"rating":0}`},
+		{"rating too high", `{"rating":6}`},
+		{"negative rating", `{"rating":-3}`},
+		{"fractional rating", `{"rating":4.5}`},
+		{"body too long", `{"rating":4,"body":"` + tooLong + `"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			SaveHandler(w, request(http.MethodPut, "/", tt.body, map[string]string{"place_id": "konark"}))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestListHandlerRejectsInvalidPages(t *testing.T) {
+	at := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"zero limit", "limit=0"},
+		{"unknown order", "order=best"},
+		{"garbage cursor", "cursor=garbage"},
+		// Reviews page on their numeric id, so another list's cursor won't do.
+		{"non-numeric cursor id", "cursor=" + pagination.Cursor{Time: at, ID: "konark", Desc: true}.Encode()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ListHandler(w, request(http.MethodGet, "/?"+tt.query, "", map[string]string{"place_id": "konark"}))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestVoteRejectsBadIDs(t *testing.T) {
+	for _, id := range []string{"", "abc", "1.5", "99999999999999999999"} {
+		for _, h := range []http.HandlerFunc{HelpfulHandler, UnhelpfulHandler} {
+			w := httptest.NewRecorder()
+			h(w, request(http.MethodPost, "/", "", map[string]string{"id": id}))
+			if w.Code != http.StatusNotFound {
+				t.Errorf("id %q: status = %d, want %d", id, w.Code, http.StatusNotFound)
+			}
+		}
+	}
+}
+
+func TestReviewError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{findplaces.ErrPlaceNotFound, http.StatusNotFound},
+		{ErrReviewNotFound, http.StatusNotFound},
+		{ErrNotVisited, http.StatusForbidden},
+		{ErrNotVerified, http.StatusForbidden},
+		{ErrOwnReview, http.StatusBadRequest},
+		{fmt.Errorf("saving review: %w", ErrNotVerified), http.StatusForbidden},
+		{errors.New("connection reset"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			reviewError(w, httptest.NewRequest(http.MethodGet, "/", nil), tt.err)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+// Package reviews lets users rate and review places they've visited, and
+// keeps each place's average rating in the catalog.
+package reviews
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"example.com/m/apis/findplaces"
+	"example.com/m/db"
+	"example.com/m/events"
+	"example.com/m/pagination"
+	"github.com/jackc/pgx/v5"
+)
+
+const maxBody = 2000
+
+var (
+	ErrReviewNotFound = errors.New("review not found")
+	ErrNotVisited     = errors.New("only visited places can be reviewed")
+	ErrNotVerified    = errors.New("only places with a verified visit can be reviewed")
+	ErrOwnReview      = errors.New("users can't vote on their own reviews")
+)
+
+type Review struct {
+	ID              int64   `json:"id"`
+	PlaceID         string  `json:"place_id"`
+	UserUUID        string  `json:"user_uuid"`
+	AuthorName      string  `json:"author_name"`
+	AuthorAvatarURL *string `json:"author_avatar_url,omitempty"`
+	Rating          int     `json:"rating" example:"4"`
+	Body            string  `json:"body"`
+	// VerifiedVisit marks reviews by someone with a verified visit to the
+	// place.
+	VerifiedVisit bool `json:"verified_visit"`
+	HelpfulCount  int  `json:"helpful_count"`
+	// Helpful is whether the caller voted this review helpful.
+	Helpful   bool      `json:"helpful"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// requireVerifiedVisit reports whether reviewing needs a verified visit
+// rather than any logged one.
+func requireVerifiedVisit() bool {
+	v, _ := strconv.ParseBool(os.Getenv("REVIEWS_REQUIRE_VERIFIED_VISIT"))
+	return v
+}
+
+func visitStatus(ctx context.Context, userUUID, placeID string) (visited, verified bool, err error) {
+	err = db.Conn.QueryRow(ctx,
+		`SELECT COUNT(*) > 0, COALESCE(BOOL_OR(verified), FALSE)
+		 FROM user_visit_history WHERE user_uuid = $1 AND place_id = $2`,
+		userUUID, placeID,
+	).Scan(&visited, &verified)
+	return visited, verified, err
+}
+
+// refreshRating recomputes the place's average rating and review count.
+func refreshRating(ctx context.Context, placeID string) error {
+	_, err := db.Conn.Exec(ctx,
+		`UPDATE places SET rating_avg = s.avg, rating_count = s.n
+		 FROM (SELECT ROUND(AVG(rating), 2)::float8 AS avg, COUNT(*) AS n
+		       FROM place_reviews WHERE place_id = $1) s
+		 WHERE place_id = $1`,
+		placeID,
+	)
+	return err
+}
+
+// reviewColumns selects a Review from place_reviews r joined to users u. $1
+// must be the viewer's UUID, which Helpful is worked out for.
+const reviewColumns = `r.id, r.place_id, r.user_uuid::text, COALESCE(u.name, ''), u.avatar_url,
+	r.rating, r.body, r.verified_visit, r.helpful_count,
+	EXISTS (SELECT 1 FROM review_votes v WHERE v.review_id = r.id AND v.user_uuid = $1),
+	r.created_at, r.updated_at`
+
+func reviewDest(rv *Review) []any {
+	return []any{&rv.ID, &rv.PlaceID, &rv.UserUUID, &rv.AuthorName, &rv.AuthorAvatarURL,
+		&rv.Rating, &rv.Body, &rv.VerifiedVisit, &rv.HelpfulCount, &rv.Helpful,
+		&rv.CreatedAt, &rv.UpdatedAt}
+}
+
+// SaveReview creates the user's review of a place or replaces it. created
+// is false for an edit.
+func SaveReview(ctx context.Context, userUUID, placeID string, rating int, body string) (rv Review, created bool, err error) {
+	// Reviews only count toward a place the catalog knows.
+	if _, err := findplaces.LookupPlace(ctx, db.Conn, findplaces.DefaultProvider(), placeID); err != nil {
+		return Review{}, false, err
+	}
+
+	visited, verified, err := visitStatus(ctx, userUUID, placeID)
+	if err != nil {
+		return Review{}, false, err
+	}
+	switch {
+	case !visited:
+		return Review{}, false, ErrNotVisited
+	case !verified && requireVerifiedVisit():
+		return Review{}, false, ErrNotVerified
+	}
+
+	var id int64
+	err = db.Conn.QueryRow(ctx,
+		`INSERT INTO place_reviews (place_id, user_uuid, rating, body, verified_visit)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (place_id, user_uuid) DO UPDATE SET rating = EXCLUDED.rating,
+			body = EXCLUDED.body, verified_visit = EXCLUDED.verified_visit, updated_at = NOW()
+		 RETURNING id, xmax = 0`,
+		placeID, userUUID, rating, body, verified,
+	).Scan(&id, &created)
+	if err != nil {
+		return Review{}, false, err
+	}
+	if err := refreshRating(ctx, placeID); err != nil {
+		return Review{}, false, err
+	}
+
+	events.Publish(ctx, events.Event{
+		Kind:     events.Review,
+		UserUUID: userUUID,
+		PlaceID:  placeID,
+		Verified: verified,
+		Ref:      strconv.FormatInt(id, 10),
+	})
+
+	rv, err = UserReview(ctx, userUUID, placeID)
+	return rv, created, err
+}
+
+// UserReview returns the user's own review of a place.
+func UserReview(ctx context.Context, userUUID, placeID string) (Review, error) {
+	var rv Review
+	err := db.Conn.QueryRow(ctx,
+		`SELECT `+reviewColumns+`
+		 FROM place_reviews r LEFT JOIN users u ON u.uuid = r.user_uuid
+		 WHERE r.user_uuid = $1 AND r.place_id = $2`,
+		userUUID, placeID,
+	).Scan(reviewDest(&rv)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Review{}, ErrReviewNotFound
+	}
+	return rv, err
+}
+
+func DeleteReview(ctx context.Context, userUUID, placeID string) error {
+	tag, err := db.Conn.Exec(ctx,
+		`DELETE FROM place_reviews WHERE user_uuid = $1 AND place_id = $2`, userUUID, placeID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrReviewNotFound
+	}
+	return refreshRating(ctx, placeID)
+}
+
+// ListReviews pages through a place's reviews by when they were written,
+// marking the ones viewerUUID found helpful. Ties are broken on the numeric
+// review id, so the cursor's id must be a number.
+func ListReviews(ctx context.Context, viewerUUID, placeID string, page pagination.Page, verifiedOnly bool) ([]Review, string, error) {
+	if page.After != nil {
+		if _, err := strconv.ParseInt(page.After.ID, 10, 64); err != nil {
+			return nil, "", pagination.ErrInvalidCursor
+		}
+	}
+
+	args := []any{viewerUUID, placeID}
+	query := `SELECT ` + reviewColumns + `
+		 FROM place_reviews r LEFT JOIN users u ON u.uuid = r.user_uuid
+		 WHERE r.place_id = $2` + page.Where("r.created_at", "r.id", &args)
+	if verifiedOnly {
+		query += ` AND r.verified_visit`
+	}
+	query += page.OrderBy("r.created_at", "r.id")
+
+	rows, err := db.Conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	list := []Review{}
+	for rows.Next() {
+		var rv Review
+		if err := rows.Scan(reviewDest(&rv)...); err != nil {
+			return nil, "", err
+		}
+		list = append(list, rv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	list, next := pagination.Trim(page, list, func(rv Review) (time.Time, string) {
+		return rv.CreatedAt, strconv.FormatInt(rv.ID, 10)
+	})
+	return list, next, nil
+}
+
+// PlaceRating returns a place's average rating (nil until it has reviews)
+// and review count.
+func PlaceRating(ctx context.Context, placeID string) (*float64, int, error) {
+	var avg *float64
+	var n int
+	err := db.Conn.QueryRow(ctx,
+		`SELECT rating_avg, rating_count FROM places WHERE place_id = $1`, placeID,
+	).Scan(&avg, &n)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, 0, nil
+	}
+	return avg, n, err
+}
+
+// Vote marks a review helpful for the user, or takes that back, and returns
+// the review's new helpful count. Voting twice the same way is a no-op.
+func Vote(ctx context.Context, userUUID string, reviewID int64, helpful bool) (int, error) {
+	var author string
+	err := db.Conn.QueryRow(ctx,
+		`SELECT user_uuid::text FROM place_reviews WHERE id = $1`, reviewID,
+	).Scan(&author)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrReviewNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if author == userUUID {
+		return 0, ErrOwnReview
+	}
+
+	query := `INSERT INTO review_votes (review_id, user_uuid) VALUES ($1, $2)
+		 ON CONFLICT (review_id, user_uuid) DO NOTHING`
+	delta := 1
+	if !helpful {
+		query = `DELETE FROM review_votes WHERE review_id = $1 AND user_uuid = $2`
+		delta = -1
+	}
+	tag, err := db.Conn.Exec(ctx, query, reviewID, userUUID)
+	if err != nil {
+		return 0, err
+	}
+	if tag.RowsAffected() == 0 {
+		delta = 0
+	}
+
+	var count int
+	err = db.Conn.QueryRow(ctx,
+		`UPDATE place_reviews SET helpful_count = GREATEST(helpful_count + $2, 0)
+		 WHERE id = $1 RETURNING helpful_count`,
+		reviewID, delta,
+	).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,24 @@
+package reviews
+
+import "testing"
+
+func TestRequireVerifiedVisit(t *testing.T) {
+	tests := []struct {
+		env  string
+		want bool
+	}{
+		{"", false},
+		{"false", false},
+		{"0", false},
+		{"true", true},
+		{"1", true},
+		{"TRUE", true},
+		{"yes", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("REVIEWS_REQUIRE_VERIFIED_VISIT", tt.env)
+		if got := requireVerifiedVisit(); got != tt.want {
+			t.Errorf("requireVerifiedVisit() with %q = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package tiles
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"example.com/m/apis/findplaces"
+	"example.com/m/db"
+	"example.com/m/i18n"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	maxZoom = 22
+
+	// clusterMaxZoom is the last zoom level at which nearby places are merged
+	// into clusters; from there on every place is its own feature.
+	clusterMaxZoom = 13
+
+	// clusterCell is the clustering grid cell size in tile units.
+	clusterCell = 256
+
+	// tileBuffer extends each tile by this many tile units on every side, so
+	// symbols straddling a tile edge render on both tiles.
+	tileBuffer = 64
+
+	maxPlacesPerTile = 20000
+)
+
+// tileBounds returns the lon/lat box of an XYZ web mercator tile.
+func tileBounds(z, x, y int) findplaces.BBox {
+	n := math.Exp2(float64(z))
+	lon := func(x float64) float64 { return x/n*360 - 180 }
+	lat := func(y float64) float64 { return math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi }
+	return findplaces.BBox{lon(float64(x)), lat(float64(y + 1)), lon(float64(x + 1)), lat(float64(y))}
+}
+
+// project maps lon/lat to tile units (0..tileExtent inside the tile).
+func project(lat, lon float64, z, x, y int) (int, int) {
+	n := math.Exp2(float64(z))
+	latR := lat * math.Pi / 180
+	px := ((lon+180)/360*n - float64(x)) * tileExtent
+	py := ((1-math.Log(math.Tan(latR)+1/math.Cos(latR))/math.Pi)/2*n - float64(y)) * tileExtent
+	return int(math.Round(px)), int(math.Round(py))
+}
+
+// bufferedBounds grows the tile's bounds by tileBuffer units, clamped to the
+// mercator world.
+func bufferedBounds(z, x, y int) findplaces.BBox {
+	b := tileBounds(z, x, y)
+	pad := float64(tileBuffer) / tileExtent
+	dLon := (b.East() - b.West()) * pad
+	dLat := (b.North() - b.South()) * pad
+	return findplaces.BBox{
+		math.Max(b.West()-dLon, -180), math.Max(b.South()-dLat, -85.0511),
+		math.Min(b.East()+dLon, 180), math.Min(b.North()+dLat, 85.0511),
+	}
+}
+
+// buildTile renders places into a "places" layer, clustering on a grid below
+// clusterMaxZoom. places come best first, so each cluster is named after its
+// best place.
+func buildTile(places []findplaces.Place, z, x, y int) []byte {
+	l := newLayer("places")
+
+	type cell struct {
+		sumX, sumY int
+		members    []findplaces.Place
+	}
+	cells := map[[2]int]*cell{}
+	var order [][2]int
+
+	for _, p := range places {
+		px, py := project(p.Lat, p.Lon, z, x, y)
+		if px < -tileBuffer || px > tileExtent+tileBuffer || py < -tileBuffer || py > tileExtent+tileBuffer {
+			continue
+		}
+
+		if z > clusterMaxZoom {
+			l.add(mvtFeature{X: px, Y: py, Props: placeProps(p)})
+			continue
+		}
+
+		k := [2]int{floorDiv(px, clusterCell), floorDiv(py, clusterCell)}
+		c, ok := cells[k]
+		if !ok {
+			c = &cell{}
+			cells[k] = c
+			order = append(order, k)
+		}
+		c.sumX += px
+		c.sumY += py
+		c.members = append(c.members, p)
+	}
+
+	for _, k := range order {
+		c := cells[k]
+		n := len(c.members)
+		cx, cy := c.sumX/n, c.sumY/n
+
+		if n == 1 {
+			l.add(mvtFeature{X: cx, Y: cy, Props: placeProps(c.members[0])})
+			continue
+		}
+		l.add(mvtFeature{X: cx, Y: cy, Props: []mvtProp{
+			{"cluster", true},
+			{"point_count", n},
+			{"id", c.members[0].PlaceID},
+			{"name", c.members[0].Name},
+		}})
+	}
+
+	return encodeTile(l)
+}
+
+func placeProps(p findplaces.Place) []mvtProp {
+	props := []mvtProp{{"id", p.PlaceID}, {"name", p.Name}}
+	if len(p.Categories) > 0 {
+		props = append(props, mvtProp{"category", p.Categories[0]})
+	}
+	return props
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// TileHandler serves catalog places as a Mapbox Vector Tile
+// @Summary Places Vector Tile
+// @Description Catalog places as a Mapbox Vector Tile (layer "places"). Below zoom 14 nearby places are merged into cluster features with point_count, named after their most reviewed place. A tile that holds too many places keeps the most reviewed and best rated.
+// @Tags places
+// @Produce application/vnd.mapbox-vector-tile
+// @Security BearerAuth
+// @Param z path int true "Zoom"
+// @Param x path int true "Tile column"
+// @Param y path int true "Tile row"
+// @Param Accept-Language header string false "Preferred languages for place names, e.g. or, hi;q=0.8"
+// @Success 200 {file} binary
+// @Header 200 {string} X-Places-Truncated "true when the tile holds more places than it can carry and some were left out"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} map[string]string "Invalid tile coordinates"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /tiles/{z}/{x}/{y}.mvt [get]
+func TileHandler(w http.ResponseWriter, r *http.Request) {
+	z, errZ := strconv.Atoi(chi.URLParam(r, "z"))
+	x, errX := strconv.Atoi(chi.URLParam(r, "x"))
+	y, errY := strconv.Atoi(chi.URLParam(r, "y"))
+	if errZ != nil || errX != nil || errY != nil || z < 0 || z > maxZoom ||
+		x < 0 || y < 0 || x >= 1<<z || y >= 1<<z {
+		i18n.Error(w, r, i18n.InvalidTile, http.StatusBadRequest)
+		return
+	}
+
+	places, truncated, err := findplaces.PlacesInBox(r.Context(), db.Conn, bufferedBounds(z, x, y), maxPlacesPerTile)
+	if err != nil {
+		log.Printf("Tile query failed: %v", err)
+		i18n.Error(w, r, i18n.TileFailed, http.StatusInternalServerError)
+		return
+	}
+
+	langs := i18n.Languages(r)
+	for i := range places {
+		places[i].Localize(langs)
+	}
+
+	tile := buildTile(places, z, x, y)
+	sum := sha1.Sum(tile)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.Header().Set("Vary", "Accept-Language")
+	if truncated {
+		w.Header().Set("X-Places-Truncated", "true")
+	}
+	if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/vnd.mapbox-vector-tile")
+	w.Header().Set("Content-Length", strconv.Itoa(len(tile)))
+	w.Write(tile)
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package tiles
+
+import "math"
+
+// A minimal Mapbox Vector Tile (spec v2.1) encoder: one layer of point
+// features, written straight to protobuf wire format.
+
+const (
+	tileExtent = 4096
+
+	wireVarint = 0
+	wireBytes  = 2
+
+	geomPoint = 1
+	cmdMoveTo = 1
+)
+
+type mvtFeature struct {
+	X, Y  int
+	Props []mvtProp
+}
+
+type mvtProp struct {
+	Key   string
+	Value any // string, int, float64 or bool
+}
+
+// layer accumulates features and interns their keys and values.
+type layer struct {
+	name     string
+	features [][]byte
+	keys     []string
+	keyIdx   map[string]int
+	values   [][]byte
+	valueIdx map[any]int
+}
+
+func newLayer(name string) *layer {
+	return &layer{name: name, keyIdx: map[string]int{}, valueIdx: map[any]int{}}
+}
+
+func (l *layer) key(k string) uint64 {
+	if i, ok := l.keyIdx[k]; ok {
+		return uint64(i)
+	}
+	l.keyIdx[k] = len(l.keys)
+	l.keys = append(l.keys, k)
+	return uint64(len(l.keys) - 1)
+}
+
+func (l *layer) value(v any) uint64 {
+	if i, ok := l.valueIdx[v]; ok {
+		return uint64(i)
+	}
+
+	var b []byte
+	switch v := v.(type) {
+	case string:
+		b = appendString(b, 1, v)
+	case float64:
+		b = appendTag(b, 3, 1) // fixed64
+		bits := math.Float64bits(v)
+		for i := 0; i < 8; i++ {
+			b = append(b, byte(bits>>(8*i)))
+		}
+	case int:
+		b = appendTag(b, 6, wireVarint)
+		b = appendVarint(b, zigzag(v))
+	case bool:
+		b = appendTag(b, 7, wireVarint)
+		if v {
+			b = appendVarint(b, 1)
+		} else {
+			b = appendVarint(b, 0)
+		}
+	default:
+		return l.value("")
+	}
+
+	l.valueIdx[v] = len(l.values)
+	l.values = append(l.values, b)
+	return uint64(len(l.values) - 1)
+}
+
+func (l *layer) add(f mvtFeature) {
+	var tags []byte
+	for _, p := range f.Props {
+		tags = appendVarint(tags, l.key(p.Key))
+		tags = appendVarint(tags, l.value(p.Value))
+	}
+
+	var geom []byte
+	geom = appendVarint(geom, cmdMoveTo|1<<3)
+	geom = appendVarint(geom, zigzag(f.X))
+	geom = appendVarint(geom, zigzag(f.Y))
+
+	var b []byte
+	b = appendBytes(b, 2, tags)
+	b = appendTag(b, 3, wireVarint)
+	b = appendVarint(b, geomPoint)
+	b = appendBytes(b, 4, geom)
+	l.features = append(l.features, b)
+}
+
+func (l *layer) encode() []byte {
+	var b []byte
+	b = appendString(b, 1, l.name)
+	for _, f := range l.features {
+		b = appendBytes(b, 2, f)
+	}
+	for _, k := range l.keys {
+		b = appendString(b, 3, k)
+	}
+	for _, v := range l.values {
+		b = appendBytes(b, 4, v)
+	}
+	b = appendTag(b, 5, wireVarint)
+	b = appendVarint(b, tileExtent)
+	b = appendTag(b, 15, wireVarint)
+	b = appendVarint(b, 2)
+	return b
+}
+
+// encodeTile wraps layers into a Tile message.
+func encodeTile(layers ...*layer) []byte {
+	var b []byte
+	for _, l := range layers {
+		b = appendBytes(b, 3, l.encode())
+	}
+	return b
+}
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendTag(b []byte, field, wire int) []byte {
+	return appendVarint(b, uint64(field<<3|wire))
+}
+
+func appendBytes(b []byte, field int, data []byte) []byte {
+	b = appendTag(b, field, wireBytes)
+	b = appendVarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendString(b []byte, field int, s string) []byte {
+	return appendBytes(b, field, []byte(s))
+}
+
+func zigzag(v int) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
@@ -0,0 +1,235 @@
+package tiles
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// field is one decoded protobuf field.
+type field struct {
+	num  int
+	wire int
+	v    uint64 // varint and fixed64 values
+	data []byte // length-delimited values
+}
+
+func readVarint(t *testing.T, b []byte) (uint64, int) {
+	t.Helper()
+	var v uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		v |= uint64(b[i]&0x7f) << (7 * i)
+		if b[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+	t.Fatalf("truncated varint % x", b)
+	return 0, 0
+}
+
+func decodeFields(t *testing.T, b []byte) []field {
+	t.Helper()
+	var fields []field
+	for len(b) > 0 {
+		tag, n := readVarint(t, b)
+		b = b[n:]
+		f := field{num: int(tag >> 3), wire: int(tag & 7)}
+		switch f.wire {
+		case wireVarint:
+			f.v, n = readVarint(t, b)
+			b = b[n:]
+		case 1: // fixed64
+			if len(b) < 8 {
+				t.Fatalf("truncated fixed64 in field %d", f.num)
+			}
+			for i := range 8 {
+				f.v |= uint64(b[i]) << (8 * i)
+			}
+			b = b[8:]
+		case wireBytes:
+			size, n := readVarint(t, b)
+			b = b[n:]
+			if uint64(len(b)) < size {
+				t.Fatalf("field %d wants %d bytes, %d left", f.num, size, len(b))
+			}
+			f.data, b = b[:size], b[size:]
+		default:
+			t.Fatalf("field %d has unexpected wire type %d", f.num, f.wire)
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// only returns the fields numbered num, checking their wire type.
+func only(t *testing.T, fields []field, num, wire int) []field {
+	t.Helper()
+	var out []field
+	for _, f := range fields {
+		if f.num == num {
+			if f.wire != wire {
+				t.Fatalf("field %d has wire type %d, want %d", num, f.wire, wire)
+			}
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func unzigzag(v uint64) int {
+	return int(v>>1) ^ -int(v&1)
+}
+
+func TestVarint(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{1, []byte{0x01}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x80, 0x01}},
+		{300, []byte{0xac, 0x02}},
+		{tileExtent, []byte{0x80, 0x20}},
+		{math.MaxUint64, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+	}
+	for _, tt := range tests {
+		got := appendVarint(nil, tt.v)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("appendVarint(%d) = % x, want % x", tt.v, got, tt.want)
+		}
+		if v, n := readVarint(t, got); v != tt.v || n != len(got) {
+			t.Errorf("varint %d decoded as %d from %d bytes", tt.v, v, n)
+		}
+	}
+}
+
+func TestZigzag(t *testing.T) {
+	tests := []struct {
+		v    int
+		want uint64
+	}{
+		{0, 0},
+		{-1, 1},
+		{1, 2},
+		{-2, 3},
+		{2, 4},
+		{-64, 127},
+		{math.MaxInt32, math.MaxUint32 - 1},
+		{math.MinInt32, math.MaxUint32},
+	}
+	for _, tt := range tests {
+		if got := zigzag(tt.v); got != tt.want {
+			t.Errorf("zigzag(%d) = %d, want %d", tt.v, got, tt.want)
+		}
+		if got := unzigzag(zigzag(tt.v)); got != tt.v {
+			t.Errorf("zigzag(%d) round-trips to %d", tt.v, got)
+		}
+	}
+}
+
+func TestEncodeTile(t *testing.T) {
+	features := []mvtFeature{
+		{X: 10, Y: 20, Props: []mvtProp{{"name", "Sun Temple"}, {"rating", 4.5}, {"count", 12}, {"verified", true}}},
+		{X: -5, Y: 4100, Props: []mvtProp{{"name", "Jagannath Temple"}, {"count", 12}, {"verified", false}}},
+		{X: 0, Y: 0, Props: []mvtProp{{"name", "Sun Temple"}, {"count", -3}}},
+	}
+	l := newLayer("places")
+	for _, f := range features {
+		l.add(f)
+	}
+
+	tile := decodeFields(t, encodeTile(l))
+	layers := only(t, tile, 3, wireBytes)
+	if len(layers) != 1 || len(tile) != 1 {
+		t.Fatalf("tile has %d fields and %d layers, want 1 and 1", len(tile), len(layers))
+	}
+	lf := decodeFields(t, layers[0].data)
+
+	if name := only(t, lf, 1, wireBytes); len(name) != 1 || string(name[0].data) != "places" {
+		t.Errorf("layer name = %v, want places", name)
+	}
+	if ext := only(t, lf, 5, wireVarint); len(ext) != 1 || ext[0].v != tileExtent {
+		t.Errorf("extent = %v, want %d", ext, tileExtent)
+	}
+	if ver := only(t, lf, 15, wireVarint); len(ver) != 1 || ver[0].v != 2 {
+		t.Errorf("version = %v, want 2", ver)
+	}
+
+	var keys []string
+	for _, f := range only(t, lf, 3, wireBytes) {
+		keys = append(keys, string(f.data))
+	}
+	if want := []string{"name", "rating", "count", "verified"}; !slices.Equal(keys, want) {
+		t.Errorf("keys = %q, want %q (interned in first-use order)", keys, want)
+	}
+
+	var values []any
+	for _, f := range only(t, lf, 4, wireBytes) {
+		vf := decodeFields(t, f.data)
+		if len(vf) != 1 {
+			t.Fatalf("value has %d fields, want 1", len(vf))
+		}
+		switch v := vf[0]; {
+		case v.num == 1 && v.wire == wireBytes:
+			values = append(values, string(v.data))
+		case v.num == 3 && v.wire == 1:
+			values = append(values, math.Float64frombits(v.v))
+		case v.num == 6 && v.wire == wireVarint:
+			values = append(values, unzigzag(v.v))
+		case v.num == 7 && v.wire == wireVarint:
+			values = append(values, v.v == 1)
+		default:
+			t.Fatalf("value field %d with wire type %d", v.num, v.wire)
+		}
+	}
+	if want := []any{"Sun Temple", 4.5, 12, true, "Jagannath Temple", false, -3}; !slices.Equal(values, want) {
+		t.Errorf("values = %v, want %v (each stored once)", values, want)
+	}
+
+	encoded := only(t, lf, 2, wireBytes)
+	if len(encoded) != len(features) {
+		t.Fatalf("layer has %d features, want %d", len(encoded), len(features))
+	}
+	for i, ef := range encoded {
+		want := features[i]
+		ff := decodeFields(t, ef.data)
+
+		if typ := only(t, ff, 3, wireVarint); len(typ) != 1 || typ[0].v != geomPoint {
+			t.Errorf("feature %d type = %v, want point", i, typ)
+		}
+
+		geom := unpack(t, only(t, ff, 4, wireBytes))
+		if len(geom) != 3 || geom[0] != cmdMoveTo|1<<3 {
+			t.Fatalf("feature %d geometry = %v, want one MoveTo", i, geom)
+		}
+		if x, y := unzigzag(geom[1]), unzigzag(geom[2]); x != want.X || y != want.Y {
+			t.Errorf("feature %d at (%d, %d), want (%d, %d)", i, x, y, want.X, want.Y)
+		}
+
+		tags := unpack(t, only(t, ff, 2, wireBytes))
+		if len(tags) != 2*len(want.Props) {
+			t.Fatalf("feature %d has %d tags, want %d", i, len(tags), 2*len(want.Props))
+		}
+		for j, p := range want.Props {
+			if k, v := keys[tags[2*j]], values[tags[2*j+1]]; k != p.Key || v != p.Value {
+				t.Errorf("feature %d tag %d = %s=%v, want %s=%v", i, j, k, v, p.Key, p.Value)
+			}
+		}
+	}
+}
+
+// unpack decodes a single packed repeated varint field.
+func unpack(t *testing.T, fields []field) []uint64 {
+	t.Helper()
+	if len(fields) != 1 {
+		t.Fatalf("got %d packed fields, want 1", len(fields))
+	}
+	var out []uint64
+	for b := fields[0].data; len(b) > 0; {
+		v, n := readVarint(t, b)
+		out = append(out, v)
+		b = b[n:]
+	}
+	return out
+}
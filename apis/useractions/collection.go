@@ -0,0 +1,413 @@
+package useractions
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"time"
+
+	"example.com/m/db"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	RoleOwner  = "owner"
+	RoleViewer = "viewer"
+)
+
+var (
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrNotOwner           = errors.New("only the owner can change a collection")
+	ErrNotInCollection    = errors.New("place is not in the collection")
+	ErrBadOrder           = errors.New("order must list every place in the collection exactly once")
+	ErrUnknownUser        = errors.New("user not found")
+)
+
+type Collection struct {
+	ID          int64     `json:"id"`
+	OwnerUUID   string    `json:"owner_uuid,omitempty"` // hidden from anonymous share-link visitors
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Role        string    `json:"role,omitempty" enums:"owner,viewer"`
+	PlaceCount  int       `json:"place_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// ShareToken and SharedWith are only shown to the owner.
+	ShareToken *string           `json:"share_token,omitempty"`
+	SharedWith []string          `json:"shared_with,omitempty"`
+	Places     []CollectionPlace `json:"places,omitempty"`
+}
+
+type CollectionPlace struct {
+	PlaceSummary
+	Position int       `json:"position"`
+	Note     string    `json:"note"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+const collectionColumns = `c.id, c.owner_uuid::text, c.name, c.description, c.share_token,
+	c.created_at, c.updated_at,
+	(SELECT COUNT(*) FROM collection_places cp WHERE cp.collection_id = c.id)`
+
+func collectionDest(c *Collection) []any {
+	return []any{&c.ID, &c.OwnerUUID, &c.Name, &c.Description, &c.ShareToken,
+		&c.CreatedAt, &c.UpdatedAt, &c.PlaceCount}
+}
+
+// viewAs fills in the caller's role and hides owner-only fields from
+// everyone else. An empty userUUID is an anonymous share-link visitor, who
+// doesn't get to see whose collection it is either.
+func (c *Collection) viewAs(userUUID string) {
+	if userUUID != "" && c.OwnerUUID == userUUID {
+		c.Role = RoleOwner
+		return
+	}
+	c.Role = RoleViewer
+	c.ShareToken = nil
+	c.SharedWith = nil
+	if userUUID == "" {
+		c.OwnerUUID = ""
+	}
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// ListCollections returns the user's own collections and those shared with
+// them.
+func ListCollections(ctx context.Context, userUUID string) ([]Collection, error) {
+	rows, err := db.Conn.Query(ctx,
+		`SELECT `+collectionColumns+` FROM collections c
+		 WHERE c.owner_uuid = $1
+		    OR c.id IN (SELECT collection_id FROM collection_shares WHERE user_uuid = $1)
+		 ORDER BY c.updated_at DESC, c.id DESC`,
+		userUUID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Collection{}
+	for rows.Next() {
+		var c Collection
+		if err := rows.Scan(collectionDest(&c)...); err != nil {
+			return nil, err
+		}
+		c.viewAs(userUUID)
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
+
+func CreateCollection(ctx context.Context, userUUID, name, description string) (Collection, error) {
+	c := Collection{OwnerUUID: userUUID, Name: name, Description: description, Role: RoleOwner}
+	err := db.Conn.QueryRow(ctx,
+		`INSERT INTO collections (owner_uuid, name, description) VALUES ($1, $2, $3)
+		 RETURNING id, created_at, updated_at`,
+		userUUID, name, description,
+	).Scan(&c.ID, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
+// loadCollection returns a collection the user may see, or
+// ErrCollectionNotFound. Collections the user can't see are reported as
+// missing rather than forbidden.
+func loadCollection(ctx context.Context, userUUID string, id int64) (Collection, error) {
+	var c Collection
+	err := db.Conn.QueryRow(ctx,
+		`SELECT `+collectionColumns+` FROM collections c
+		 WHERE c.id = $1 AND (c.owner_uuid = $2
+		    OR EXISTS (SELECT 1 FROM collection_shares s WHERE s.collection_id = c.id AND s.user_uuid = $2))`,
+		id, userUUID,
+	).Scan(collectionDest(&c)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Collection{}, ErrCollectionNotFound
+	}
+	return c, err
+}
+
+// ownCollection is loadCollection for changes: viewers get ErrNotOwner.
+func ownCollection(ctx context.Context, userUUID string, id int64) (Collection, error) {
+	c, err := loadCollection(ctx, userUUID, id)
+	if err == nil && c.OwnerUUID != userUUID {
+		return Collection{}, ErrNotOwner
+	}
+	return c, err
+}
+
+// collectionPlaces returns a collection's places in order, joined to the
+// catalog. Places missing from the catalog come back with just their
+// place_id; with backfill, they are also queued for a provider lookup.
+func collectionPlaces(ctx context.Context, id int64, backfill bool) ([]CollectionPlace, error) {
+	rows, err := db.Conn.Query(ctx,
+		`SELECT t.place_id, t.position, t.note, t.added_at, `+summaryColumns+`
+		 FROM collection_places t LEFT JOIN places p ON p.place_id = t.place_id
+		 WHERE t.collection_id = $1
+		 ORDER BY t.position, t.added_at`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	places := []CollectionPlace{}
+	var missing []string
+	for rows.Next() {
+		var cp CollectionPlace
+		var found bool
+		dest := append([]any{&cp.PlaceID, &cp.Position, &cp.Note, &cp.AddedAt}, summaryDest(&cp.PlaceSummary, &found)...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		if !found {
+			missing = append(missing, cp.PlaceID)
+		}
+		places = append(places, cp)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if backfill {
+		backfillLater(ctx, missing)
+	}
+	return places, nil
+}
+
+func collectionShares(ctx context.Context, id int64) ([]string, error) {
+	rows, err := db.Conn.Query(ctx,
+		`SELECT user_uuid::text FROM collection_shares WHERE collection_id = $1 ORDER BY created_at`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []string{}
+	for rows.Next() {
+		var u string
+		if err := rows.Scan(&u); err == nil {
+			users = append(users, u)
+		}
+	}
+	return users, rows.Err()
+}
+
+// GetCollection returns a collection with its places, as userUUID sees it.
+func GetCollection(ctx context.Context, userUUID string, id int64) (Collection, error) {
+	c, err := loadCollection(ctx, userUUID, id)
+	if err != nil {
+		return Collection{}, err
+	}
+	return withPlaces(ctx, c, userUUID)
+}
+
+// withPlaces fills in the collection's places and, for its owner, who it is
+// shared with. Anonymous callers (userUUID "") get catalog data only, so a
+// public link can't be used to spend provider quota.
+func withPlaces(ctx context.Context, c Collection, userUUID string) (Collection, error) {
+	var err error
+	if c.Places, err = collectionPlaces(ctx, c.ID, userUUID != ""); err != nil {
+		return Collection{}, err
+	}
+	if userUUID != "" && c.OwnerUUID == userUUID {
+		if c.SharedWith, err = collectionShares(ctx, c.ID); err != nil {
+			return Collection{}, err
+		}
+	}
+	c.viewAs(userUUID)
+	return c, nil
+}
+
+// SharedCollection returns the collection behind a public share link.
+func SharedCollection(ctx context.Context, token string) (Collection, error) {
+	var c Collection
+	err := db.Conn.QueryRow(ctx,
+		`SELECT `+collectionColumns+` FROM collections c WHERE c.share_token = $1`, token,
+	).Scan(collectionDest(&c)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Collection{}, ErrCollectionNotFound
+	}
+	if err != nil {
+		return Collection{}, err
+	}
+	return withPlaces(ctx, c, "")
+}
+
+// UpdateCollection renames or redescribes a collection; nil leaves a field
+// as it is.
+func UpdateCollection(ctx context.Context, userUUID string, id int64, name, description *string) error {
+	if _, err := ownCollection(ctx, userUUID, id); err != nil {
+		return err
+	}
+	_, err := db.Conn.Exec(ctx,
+		`UPDATE collections SET name = COALESCE($2, name), description = COALESCE($3, description),
+			updated_at = NOW()
+		 WHERE id = $1`,
+		id, name, description,
+	)
+	return err
+}
+
+func DeleteCollection(ctx context.Context, userUUID string, id int64) error {
+	if _, err := ownCollection(ctx, userUUID, id); err != nil {
+		return err
+	}
+	_, err := db.Conn.Exec(ctx, `DELETE FROM collections WHERE id = $1`, id)
+	return err
+}
+
+func touchCollection(ctx context.Context, id int64) {
+	db.Conn.Exec(ctx, `UPDATE collections SET updated_at = NOW() WHERE id = $1`, id)
+}
+
+// AddToCollection appends a place (or updates its note if it's already
+// there). The place is saved for the user too.
+func AddToCollection(ctx context.Context, userUUID string, id int64, placeID, note string) error {
+	if _, err := ownCollection(ctx, userUUID, id); err != nil {
+		return err
+	}
+	_, err := db.Conn.Exec(ctx,
+		`INSERT INTO collection_places (collection_id, place_id, position, note)
+		 SELECT $1, $2, COALESCE(MAX(position) + 1, 0), $3
+		 FROM collection_places WHERE collection_id = $1
+		 ON CONFLICT (collection_id, place_id) DO UPDATE SET note = EXCLUDED.note`,
+		id, placeID, note,
+	)
+	if err != nil {
+		return err
+	}
+	touchCollection(ctx, id)
+	return SavePlace(ctx, userUUID, placeID)
+}
+
+func SetCollectionNote(ctx context.Context, userUUID string, id int64, placeID, note string) error {
+	if _, err := ownCollection(ctx, userUUID, id); err != nil {
+		return err
+	}
+	tag, err := db.Conn.Exec(ctx,
+		`UPDATE collection_places SET note = $3 WHERE collection_id = $1 AND place_id = $2`,
+		id, placeID, note,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotInCollection
+	}
+	touchCollection(ctx, id)
+	return nil
+}
+
+func RemoveFromCollection(ctx context.Context, userUUID string, id int64, placeID string) error {
+	if _, err := ownCollection(ctx, userUUID, id); err != nil {
+		return err
+	}
+	_, err := db.Conn.Exec(ctx,
+		`DELETE FROM collection_places WHERE collection_id = $1 AND place_id = $2`, id, placeID)
+	touchCollection(ctx, id)
+	return err
+}
+
+// ReorderCollection puts the collection's places in the order given, which
+// must name each of them exactly once.
+func ReorderCollection(ctx context.Context, userUUID string, id int64, placeIDs []string) error {
+	if _, err := ownCollection(ctx, userUUID, id); err != nil {
+		return err
+	}
+
+	rows, err := db.Conn.Query(ctx, `SELECT place_id FROM collection_places WHERE collection_id = $1`, id)
+	if err != nil {
+		return err
+	}
+	current, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+
+	if !isPermutation(current, placeIDs) {
+		return ErrBadOrder
+	}
+
+	_, err = db.Conn.Exec(ctx,
+		`UPDATE collection_places cp SET position = o.n - 1
+		 FROM unnest($2::text[]) WITH ORDINALITY AS o(place_id, n)
+		 WHERE cp.collection_id = $1 AND cp.place_id = o.place_id`,
+		id, placeIDs,
+	)
+	touchCollection(ctx, id)
+	return err
+}
+
+// isPermutation reports whether order lists every element of current exactly
+// once: nothing missing, nothing extra, no duplicates.
+func isPermutation[T cmp.Ordered](current, order []T) bool {
+	if len(current) != len(order) {
+		return false
+	}
+	a, b := slices.Clone(current), slices.Clone(order)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(a, b)
+}
+
+// SetShareLink creates (or replaces) the collection's public link token, or
+// revokes it when enable is false.
+func SetShareLink(ctx context.Context, userUUID string, id int64, enable bool) (*string, error) {
+	if _, err := ownCollection(ctx, userUUID, id); err != nil {
+		return nil, err
+	}
+
+	var token *string
+	if enable {
+		t, err := newShareToken()
+		if err != nil {
+			return nil, err
+		}
+		token = &t
+	}
+	_, err := db.Conn.Exec(ctx,
+		`UPDATE collections SET share_token = $2, updated_at = NOW() WHERE id = $1`, id, token)
+	return token, err
+}
+
+func ShareCollection(ctx context.Context, userUUID string, id int64, withUUID string) error {
+	if _, err := ownCollection(ctx, userUUID, id); err != nil {
+		return err
+	}
+	tag, err := db.Conn.Exec(ctx,
+		`INSERT INTO collection_shares (collection_id, user_uuid)
+		 SELECT $1, uuid FROM users WHERE uuid = $2
+		 ON CONFLICT (collection_id, user_uuid) DO NOTHING`,
+		id, withUUID,
+	)
+	if err != nil || tag.RowsAffected() > 0 {
+		return err
+	}
+
+	var exists bool
+	db.Conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE uuid = $1)`, withUUID).Scan(&exists)
+	if !exists {
+		return ErrUnknownUser
+	}
+	return nil
+}
+
+func UnshareCollection(ctx context.Context, userUUID string, id int64, withUUID string) error {
+	if _, err := ownCollection(ctx, userUUID, id); err != nil {
+		return err
+	}
+	_, err := db.Conn.Exec(ctx,
+		`DELETE FROM collection_shares WHERE collection_id = $1 AND user_uuid = $2`, id, withUUID)
+	return err
+}
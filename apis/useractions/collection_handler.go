@@ -0,0 +1,641 @@
+package useractions
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"example.com/m/apis/findplaces"
+	"example.com/m/i18n"
+	"example.com/m/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	maxCollectionName = 100
+	maxCollectionText = 1000
+)
+
+// sharedLimiter throttles the public share-link endpoint per client address.
+var sharedLimiter = findplaces.EnvLimiter("SHARED_COLLECTIONS_PER_MINUTE", 30)
+
+type CollectionRequest struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// valid checks the fields that are set; creating additionally requires a
+// name.
+func (req CollectionRequest) valid() bool {
+	if req.Name != nil {
+		*req.Name = strings.TrimSpace(*req.Name)
+		if n := utf8.RuneCountInString(*req.Name); n == 0 || n > maxCollectionName {
+			return false
+		}
+	}
+	return req.Description == nil || utf8.RuneCountInString(*req.Description) <= maxCollectionText
+}
+
+type CollectionPlaceRequest struct {
+	PlaceID string `json:"place_id"`
+	Note    string `json:"note"`
+}
+
+type CollectionNoteRequest struct {
+	Note string `json:"note"`
+}
+
+type CollectionOrderRequest struct {
+	PlaceIDs []string `json:"place_ids"`
+}
+
+type CollectionShareRequest struct {
+	UserUUID string `json:"user_uuid"`
+}
+
+type CollectionsResponse struct {
+	Collections []Collection `json:"collections"`
+}
+
+type ShareLinkResponse struct {
+	ShareToken string `json:"share_token"`
+	URL        string `json:"url" example:"/shared/collections/3q2-7wE..."`
+}
+
+func collectionID(r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// collectionError reports a failed collection call. Collections the caller
+// can't see are 404s; ones they can see but don't own are 403s.
+func collectionError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrCollectionNotFound):
+		i18n.Error(w, r, i18n.CollectionNotFound, http.StatusNotFound)
+	case errors.Is(err, ErrNotOwner):
+		i18n.Error(w, r, i18n.NotCollectionOwner, http.StatusForbidden)
+	case errors.Is(err, ErrNotInCollection):
+		i18n.Error(w, r, i18n.NotInCollection, http.StatusNotFound)
+	case errors.Is(err, ErrBadOrder):
+		i18n.Error(w, r, i18n.InvalidOrder, http.StatusBadRequest)
+	case errors.Is(err, ErrUnknownUser):
+		i18n.Error(w, r, i18n.UserNotFound, http.StatusNotFound)
+	default:
+		log.Printf("Collection update failed: %v", err)
+		i18n.Error(w, r, i18n.CollectionFailed, http.StatusInternalServerError)
+	}
+}
+
+func writeCollection(w http.ResponseWriter, r *http.Request, c Collection) {
+	langs := i18n.Languages(r)
+	for i := range c.Places {
+		c.Places[i].localize(langs)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
+	json.NewEncoder(w).Encode(c)
+}
+
+// ListCollectionsHandler lists the user's collections
+// @Summary List Collections
+// @Description The caller's own collections and those shared with them, most recently updated first. role tells the two apart.
+// @Tags collections
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} CollectionsResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/collections [get]
+func ListCollectionsHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	list, err := ListCollections(r.Context(), userUUID)
+	if err != nil {
+		collectionError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(CollectionsResponse{Collections: list})
+}
+
+// CreateCollectionHandler creates a collection
+// @Summary Create Collection
+// @Description Create a named, initially empty and private, list of places
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CollectionRequest true "Name and optional description"
+// @Success 201 {object} Collection
+// @Failure 400 {object} map[string]string "Invalid name or description"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/collections [post]
+func CreateCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	var req CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == nil || !req.valid() {
+		i18n.Error(w, r, i18n.InvalidCollection, http.StatusBadRequest)
+		return
+	}
+	var description string
+	if req.Description != nil {
+		description = *req.Description
+	}
+
+	c, err := CreateCollection(r.Context(), userUUID, *req.Name, description)
+	if err != nil {
+		collectionError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(c)
+}
+
+// GetCollectionHandler returns a collection with its places
+// @Summary Get Collection
+// @Description A collection the caller owns or that was shared with them, with its places in order. share_token and shared_with are only shown to the owner.
+// @Tags collections
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Param Accept-Language header string false "Preferred languages for place names and messages, e.g. or, hi;q=0.8"
+// @Success 200 {object} Collection
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/collections/{id} [get]
+func GetCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, ok := collectionID(r)
+	if !ok {
+		i18n.Error(w, r, i18n.CollectionNotFound, http.StatusNotFound)
+		return
+	}
+
+	c, err := GetCollection(r.Context(), userUUID, id)
+	if err != nil {
+		collectionError(w, r, err)
+		return
+	}
+	writeCollection(w, r, c)
+}
+
+// UpdateCollectionHandler renames a collection
+// @Summary Update Collection
+// @Description Change a collection's name and/or description. Omitted fields are left as they are.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Param request body CollectionRequest true "Fields to change"
+// @Success 200 {object} map[string]string "status: updated"
+// @Failure 400 {object} map[string]string "Invalid name or description"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the owner"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/collections/{id} [patch]
+func UpdateCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, ok := collectionID(r)
+	if !ok {
+		i18n.Error(w, r, i18n.CollectionNotFound, http.StatusNotFound)
+		return
+	}
+
+	var req CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !req.valid() {
+		i18n.Error(w, r, i18n.InvalidCollection, http.StatusBadRequest)
+		return
+	}
+
+	if err := UpdateCollection(r.Context(), userUUID, id, req.Name, req.Description); err != nil {
+		collectionError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
+}
+
+// DeleteCollectionHandler deletes a collection
+// @Summary Delete Collection
+// @Description Delete a collection, its notes and its shares. The places stay saved.
+// @Tags collections
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Success 200 {object} map[string]string "status: deleted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the owner"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/collections/{id} [delete]
+func DeleteCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, ok := collectionID(r)
+	if !ok {
+		i18n.Error(w, r, i18n.CollectionNotFound, http.StatusNotFound)
+		return
+	}
+
+	if err := DeleteCollection(r.Context(), userUUID, id); err != nil {
+		collectionError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+}
+
+// AddCollectionPlaceHandler adds a place to a collection
+// @Summary Add Place to Collection
+// @Description Append a place, with an optional note, to the end of a collection; the place is also saved for the caller. Adding a place that's already there just replaces its note.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Param request body CollectionPlaceRequest true "Place and note"
+// @Success 200 {object} map[string]string "status: added"
+// @Failure 400 {object} map[string]string "Invalid place_id or note"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the owner"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/collections/{id}/places [post]
+func AddCollectionPlaceHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, ok := collectionID(r)
+	if !ok {
+		i18n.Error(w, r, i18n.CollectionNotFound, http.StatusNotFound)
+		return
+	}
+
+	var req CollectionPlaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.PlaceID == "" {
+		i18n.Error(w, r, i18n.InvalidPlaceID, http.StatusBadRequest)
+		return
+	}
+	if utf8.RuneCountInString(req.Note) > maxCollectionText {
+		i18n.Error(w, r, i18n.InvalidCollection, http.StatusBadRequest)
+		return
+	}
+
+	if err := AddToCollection(r.Context(), userUUID, id, req.PlaceID, req.Note); err != nil {
+		collectionError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "added"})
+}
+
+// UpdateCollectionPlaceHandler changes a place's note
+// @Summary Update Collection Note
+// @Description Replace the note on a place in a collection
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Param place_id path string true "Place ID"
+// @Param request body CollectionNoteRequest true "Note"
+// @Success 200 {object} map[string]string "status: updated"
+// @Failure 400 {object} map[string]string "Invalid note"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the owner"
+// @Failure 404 {object} map[string]string "Collection or place not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/collections/{id}/places/{place_id} [patch]
+func UpdateCollectionPlaceHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, ok := collectionID(r)
+	if !ok {
+		i18n.Error(w, r, i18n.CollectionNotFound, http.StatusNotFound)
+		return
+	}
+
+	var req CollectionNoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || utf8.RuneCountInString(req.Note) > maxCollectionText {
+		i18n.Error(w, r, i18n.InvalidCollection, http.StatusBadRequest)
+		return
+	}
+
+	if err := SetCollectionNote(r.Context(), userUUID, id, chi.URLParam(r, "place_id"), req.Note); err != nil {
+		collectionError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
+}
+
+// RemoveCollectionPlaceHandler removes a place from a collection
+// @Summary Remove Place from Collection
+// @Description Remove a place from a collection. It stays in the caller's saved places.
+// @Tags collections
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Param place_id path string true "Place ID"
+// @Success 200 {object} map[string]string "status: removed"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the owner"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/collections/{id}/places/{place_id} [delete]
+func RemoveCollectionPlaceHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, ok := collectionID(r)
+	if !ok {
+		i18n.Error(w, r, i18n.CollectionNotFound, http.StatusNotFound)
+		return
+	}
+
+	if err := RemoveFromCollection(r.Context(), userUUID, id, chi.URLParam(r, "place_id")); err != nil {
+		collectionError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "removed"})
+}
+
+// ReorderCollectionHandler reorders a collection's places
+// @Summary Reorder Collection
+// @Description Set the order of a collection's places. place_ids must list every place in the collection exactly once.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Param request body CollectionOrderRequest true "Place IDs in their new order"
+// @Success 200 {object} map[string]string "status: reordered"
+// @Failure 400 {object} map[string]string "Invalid order"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the owner"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/collections/{id}/order [put]
+func ReorderCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, ok := collectionID(r)
+	if !ok {
+		i18n.Error(w, r, i18n.CollectionNotFound, http.StatusNotFound)
+		return
+	}
+
+	var req CollectionOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.PlaceIDs == nil {
+		i18n.Error(w, r, i18n.InvalidOrder, http.StatusBadRequest)
+		return
+	}
+
+	if err := ReorderCollection(r.Context(), userUUID, id, req.PlaceIDs); err != nil {
+		collectionError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "reordered"})
+}
+
+// CreateShareLinkHandler makes a collection readable by link
+// @Summary Create Share Link
+// @Description Create a public read-only link to a collection. Calling it again replaces the link, so anyone holding the old one loses access.
+// @Tags collections
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Success 200 {object} ShareLinkResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the owner"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/collections/{id}/share-link [post]
+func CreateShareLinkHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, ok := collectionID(r)
+	if !ok {
+		i18n.Error(w, r, i18n.CollectionNotFound, http.StatusNotFound)
+		return
+	}
+
+	token, err := SetShareLink(r.Context(), userUUID, id, true)
+	if err != nil {
+		collectionError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ShareLinkResponse{ShareToken: *token, URL: "/shared/collections/" + *token})
+}
+
+// RevokeShareLinkHandler turns off a collection's public link
+// @Summary Revoke Share Link
+// @Description Turn off a collection's public link. Users it was shared with directly keep access.
+// @Tags collections
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Success 200 {object} map[string]string "status: revoked"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the owner"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/collections/{id}/share-link [delete]
+func RevokeShareLinkHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, ok := collectionID(r)
+	if !ok {
+		i18n.Error(w, r, i18n.CollectionNotFound, http.StatusNotFound)
+		return
+	}
+
+	if _, err := SetShareLink(r.Context(), userUUID, id, false); err != nil {
+		collectionError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "revoked"})
+}
+
+// ShareCollectionHandler shares a collection with a user
+// @Summary Share Collection
+// @Description Give another user read-only access to a collection. It then shows up in their collection list.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Param request body CollectionShareRequest true "User to share with"
+// @Success 200 {object} map[string]string "status: shared"
+// @Failure 400 {object} map[string]string "Invalid user_uuid"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the owner"
+// @Failure 404 {object} map[string]string "Collection or user not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/collections/{id}/shares [post]
+func ShareCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, ok := collectionID(r)
+	if !ok {
+		i18n.Error(w, r, i18n.CollectionNotFound, http.StatusNotFound)
+		return
+	}
+
+	var req CollectionShareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		i18n.Error(w, r, i18n.InvalidShareUser, http.StatusBadRequest)
+		return
+	}
+	with, err := uuid.Parse(req.UserUUID)
+	if err != nil || with.String() == userUUID {
+		i18n.Error(w, r, i18n.InvalidShareUser, http.StatusBadRequest)
+		return
+	}
+
+	if err := ShareCollection(r.Context(), userUUID, id, with.String()); err != nil {
+		collectionError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "shared"})
+}
+
+// UnshareCollectionHandler stops sharing a collection with a user
+// @Summary Unshare Collection
+// @Description Take away a user's access to a collection
+// @Tags collections
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Collection ID"
+// @Param user_uuid path string true "User UUID"
+// @Success 200 {object} map[string]string "status: unshared"
+// @Failure 400 {object} map[string]string "Invalid user_uuid"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the owner"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/collections/{id}/shares/{user_uuid} [delete]
+func UnshareCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, ok := collectionID(r)
+	if !ok {
+		i18n.Error(w, r, i18n.CollectionNotFound, http.StatusNotFound)
+		return
+	}
+	with, err := uuid.Parse(chi.URLParam(r, "user_uuid"))
+	if err != nil {
+		i18n.Error(w, r, i18n.InvalidShareUser, http.StatusBadRequest)
+		return
+	}
+
+	if err := UnshareCollection(r.Context(), userUUID, id, with.String()); err != nil {
+		collectionError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "unshared"})
+}
+
+// SharedCollectionHandler serves a collection by its public link
+// @Summary Shared Collection
+// @Description Read-only view of a collection through its share link. No sign-in needed.
+// @Tags collections
+// @Produce json
+// @Param token path string true "Share token"
+// @Param Accept-Language header string false "Preferred languages for place names and messages, e.g. or, hi;q=0.8"
+// @Success 200 {object} Collection
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 429 {object} map[string]string "Too many requests"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /shared/collections/{token} [get]
+func SharedCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	client, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		client = r.RemoteAddr
+	}
+	if ok, wait := sharedLimiter().Allow(client); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		i18n.Error(w, r, i18n.TooManyRequests, http.StatusTooManyRequests)
+		return
+	}
+
+	c, err := SharedCollection(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		collectionError(w, r, err)
+		return
+	}
+	writeCollection(w, r, c)
+}
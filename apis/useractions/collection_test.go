@@ -0,0 +1,27 @@
+package useractions
+
+import "testing"
+
+func TestIsPermutation(t *testing.T) {
+	tests := []struct {
+		name           string
+		current, order []string
+		want           bool
+	}{
+		{"same order", []string{"a", "b", "c"}, []string{"a", "b", "c"}, true},
+		{"reordered", []string{"a", "b", "c"}, []string{"c", "a", "b"}, true},
+		{"both empty", []string{}, []string{}, true},
+		{"missing", []string{"a", "b", "c"}, []string{"a", "b"}, false},
+		{"extra", []string{"a", "b"}, []string{"a", "b", "c"}, false},
+		{"duplicate in place of another", []string{"a", "b", "c"}, []string{"a", "b", "b"}, false},
+		{"duplicate on top", []string{"a", "b"}, []string{"a", "b", "b"}, false},
+		{"unknown", []string{"a", "b"}, []string{"a", "x"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPermutation(tt.current, tt.order); got != tt.want {
+				t.Errorf("isPermutation(%v, %v) = %v, want %v", tt.current, tt.order, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,14 +2,60 @@ package useractions
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"time"
 
+	"example.com/m/apis/findplaces"
+	"example.com/m/db"
+	"example.com/m/geoformat"
+	"example.com/m/i18n"
+	"example.com/m/pagination"
 	"example.com/m/utils"
 	"github.com/go-chi/chi/v5"
 )
 
+// PlaceRequest names a place. When logging a visit it may also carry the
+// device's location, which is what gets the visit verified.
 type PlaceRequest struct {
-	PlaceID string `json:"place_id"`
+	PlaceID        string   `json:"place_id"`
+	Latitude       *float64 `json:"latitude,omitempty"`
+	Longitude      *float64 `json:"longitude,omitempty"`
+	AccuracyMeters float64  `json:"accuracy_meters,omitempty"`
+}
+
+// location returns the request's location, or nil when it has none. ok is
+// false when the location is partial or out of range.
+func (req PlaceRequest) location() (loc *Location, ok bool) {
+	if req.Latitude == nil && req.Longitude == nil {
+		return nil, true
+	}
+	if req.Latitude == nil || req.Longitude == nil {
+		return nil, false
+	}
+	lat, lon := *req.Latitude, *req.Longitude
+	if math.IsNaN(lat) || math.IsNaN(lon) || lat < -90 || lat > 90 || lon < -180 || lon > 180 ||
+		math.IsNaN(req.AccuracyMeters) || req.AccuracyMeters < 0 {
+		return nil, false
+	}
+	return &Location{Lat: lat, Lon: lon, AccuracyMeters: req.AccuracyMeters}, true
+}
+
+type VisitResponse struct {
+	Status string `json:"status" example:"visited"`
+	VisitCheck
+}
+
+type SavedPlacesResponse struct {
+	SavedPlaces []SavedPlace `json:"saved_places"`
+	NextCursor  string       `json:"next_cursor,omitempty"`
+}
+
+type VisitHistoryResponse struct {
+	VisitHistory []Visit `json:"visit_history"`
+	NextCursor   string  `json:"next_cursor,omitempty"`
 }
 
 // SavePlaceHandler saves a place for the user
@@ -30,18 +76,18 @@ func SavePlaceHandler(w http.ResponseWriter, r *http.Request) {
 
 	userUUID, err := utils.GetUserUUIDFromCtx(ctx)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	var req PlaceRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.PlaceID == "" {
-		http.Error(w, "Invalid place_id", http.StatusBadRequest)
+		i18n.Error(w, r, i18n.InvalidPlaceID, http.StatusBadRequest)
 		return
 	}
 
 	if err := SavePlace(ctx, userUUID, req.PlaceID); err != nil {
-		http.Error(w, "Failed to save place", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.SavePlaceFailed, http.StatusInternalServerError)
 		return
 	}
 
@@ -66,19 +112,19 @@ func RemoveSavedPlaceHandler(w http.ResponseWriter, r *http.Request) {
 
 	userUUID, err := utils.GetUserUUIDFromCtx(ctx)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	placeID := chi.URLParam(r, "place_id")
 
 	if placeID == "" {
-		http.Error(w, "Missing place_id", http.StatusBadRequest)
+		i18n.Error(w, r, i18n.MissingPlaceID, http.StatusBadRequest)
 		return
 	}
 
 	if err := RemoveSavedPlace(r.Context(), userUUID, placeID); err != nil {
-		http.Error(w, "Failed to remove saved place", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.RemovePlaceFailed, http.StatusInternalServerError)
 		return
 	}
 
@@ -91,8 +137,20 @@ func RemoveSavedPlaceHandler(w http.ResponseWriter, r *http.Request) {
 // @Tags useractions
 // @Accept json
 // @Produce json
+// @Produce application/geo+json
+// @Produce application/gpx+xml
+// @Produce application/vnd.google-earth.kml+xml
 // @Security BearerAuth
-// @Success 200 {object} map[string][]string "saved_places"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param order query string false "Sort by time: desc (default) or asc"
+// @Param from query string false "Only entries at or after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Only entries before this time (RFC 3339, or YYYY-MM-DD inclusive)"
+// @Param category query string false "Only places in this category or its subcategories"
+// @Param format query string false "Output format: json, geojson, gpx or kml (overrides Accept)"
+// @Param Accept-Language header string false "Preferred languages for place names and messages, e.g. or, hi;q=0.8"
+// @Success 200 {object} SavedPlacesResponse
+// @Failure 400 {object} map[string]string "Invalid pagination parameters"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /api/user/saved-places [get]
@@ -101,30 +159,52 @@ func GetSavedPlacesHandler(w http.ResponseWriter, r *http.Request) {
 
 	userUUID, err := utils.GetUserUUIDFromCtx(ctx)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		i18n.Error(w, r, i18n.InvalidPagination, http.StatusBadRequest)
 		return
 	}
 
-	places, err := GetSavedPlaces(r.Context(), userUUID)
+	places, next, err := GetSavedPlaces(r.Context(), userUUID, page, r.URL.Query().Get("category"))
 	if err != nil {
-		http.Error(w, "Failed to fetch saved places", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.SavedPlacesFailed, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Vary", "Accept, Accept-Language")
+	langs := i18n.Languages(r)
+	for i := range places {
+		places[i].localize(langs)
+	}
+
+	if f := geoformat.Negotiate(r); f != geoformat.JSON {
+		features := make([]geoformat.Feature, len(places))
+		for i, p := range places {
+			features[i] = p.Feature(&places[i].SavedAt, p)
+		}
+		writeFeatures(w, f, "Saved places", features, next)
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string][]string{"saved_places": places})
+	json.NewEncoder(w).Encode(SavedPlacesResponse{SavedPlaces: places, NextCursor: next})
 }
 
 // LogVisitHandler logs a visit to a place
 // @Summary Log Visit
-// @Description Log a visit to a specific place
+// @Description Log a visit to a specific place. Sending the device's latitude, longitude and accuracy_meters gets the visit verified when the user is within the place's geofence; visits without a location, or failing anti-spoofing checks, are kept but unverified.
 // @Tags useractions
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body PlaceRequest true "Place Details"
-// @Success 200 {object} map[string]string "status: visited"
-// @Failure 400 {object} map[string]string "Invalid place_id"
+// @Success 200 {object} VisitResponse
+// @Failure 400 {object} map[string]string "Invalid place_id or location"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Place not found"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /api/user/visit [post]
 func LogVisitHandler(w http.ResponseWriter, r *http.Request) {
@@ -132,22 +212,50 @@ func LogVisitHandler(w http.ResponseWriter, r *http.Request) {
 
 	userUUID, err := utils.GetUserUUIDFromCtx(ctx)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	var req PlaceRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.PlaceID == "" {
-		http.Error(w, "Invalid place_id", http.StatusBadRequest)
+		i18n.Error(w, r, i18n.InvalidPlaceID, http.StatusBadRequest)
 		return
 	}
 
-	if err := LogVisit(r.Context(), userUUID, req.PlaceID); err != nil {
-		http.Error(w, "Failed to log visit", http.StatusInternalServerError)
+	at, ok := req.location()
+	if !ok {
+		i18n.Error(w, r, i18n.InvalidLocation, http.StatusBadRequest)
+		return
+	}
+
+	// Without a location there is nothing to verify against, so don't spend
+	// a place lookup on it.
+	check := VisitCheck{Reason: ReasonNoLocation}
+	if at != nil {
+		place, err := findplaces.LookupPlace(ctx, db.Conn, findplaces.DefaultProvider(), req.PlaceID)
+		switch {
+		case errors.Is(err, findplaces.ErrPlaceNotFound):
+			i18n.Error(w, r, i18n.PlaceNotFound, http.StatusNotFound)
+			return
+		case err != nil:
+			log.Printf("Visit place lookup failed: %v", err)
+			check = VisitCheck{Reason: ReasonUnknownPlace}
+		default:
+			check, err = VerifyVisit(ctx, userUUID, place, at, time.Now())
+			if err != nil {
+				log.Printf("Visit verification failed: %v", err)
+				i18n.Error(w, r, i18n.LogVisitFailed, http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
+	if err := LogVisit(ctx, userUUID, req.PlaceID, at, check); err != nil {
+		i18n.Error(w, r, i18n.LogVisitFailed, http.StatusInternalServerError)
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string]string{"status": "visited"})
+	json.NewEncoder(w).Encode(VisitResponse{Status: "visited", VisitCheck: check})
 }
 
 // GetVisitHistoryHandler returns visit history
@@ -156,8 +264,20 @@ func LogVisitHandler(w http.ResponseWriter, r *http.Request) {
 // @Tags useractions
 // @Accept json
 // @Produce json
+// @Produce application/geo+json
+// @Produce application/gpx+xml
+// @Produce application/vnd.google-earth.kml+xml
 // @Security BearerAuth
-// @Success 200 {object} map[string][]string "visit_history"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param order query string false "Sort by time: desc (default) or asc"
+// @Param from query string false "Only entries at or after this time (RFC 3339 or YYYY-MM-DD)"
+// @Param to query string false "Only entries before this time (RFC 3339, or YYYY-MM-DD inclusive)"
+// @Param category query string false "Only places in this category or its subcategories"
+// @Param format query string false "Output format: json, geojson, gpx or kml (overrides Accept)"
+// @Param Accept-Language header string false "Preferred languages for place names and messages, e.g. or, hi;q=0.8"
+// @Success 200 {object} VisitHistoryResponse
+// @Failure 400 {object} map[string]string "Invalid pagination parameters"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /api/user/visit-history [get]
@@ -166,15 +286,47 @@ func GetVisitHistoryHandler(w http.ResponseWriter, r *http.Request) {
 
 	userUUID, err := utils.GetUserUUIDFromCtx(ctx)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	page, err := pagination.FromRequest(r)
+	if err != nil {
+		i18n.Error(w, r, i18n.InvalidPagination, http.StatusBadRequest)
 		return
 	}
 
-	visits, err := GetVisitHistory(r.Context(), userUUID)
+	visits, next, err := GetVisitHistory(r.Context(), userUUID, page, r.URL.Query().Get("category"))
 	if err != nil {
-		http.Error(w, "Failed to get history", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.HistoryFailed, http.StatusInternalServerError)
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string][]string{"visit_history": visits})
+	w.Header().Set("Vary", "Accept, Accept-Language")
+	langs := i18n.Languages(r)
+	for i := range visits {
+		visits[i].localize(langs)
+	}
+
+	if f := geoformat.Negotiate(r); f != geoformat.JSON {
+		features := make([]geoformat.Feature, len(visits))
+		for i, v := range visits {
+			features[i] = v.Feature(&visits[i].VisitedAt, v)
+		}
+		writeFeatures(w, f, "Visit history", features, next)
+		return
+	}
+
+	json.NewEncoder(w).Encode(VisitHistoryResponse{VisitHistory: visits, NextCursor: next})
+}
+
+// writeFeatures renders a page of places as GeoJSON/GPX/KML. Those formats
+// have nowhere to put the cursor, so it travels in X-Next-Cursor.
+func writeFeatures(w http.ResponseWriter, f geoformat.Format, title string, features []geoformat.Feature, next string) {
+	if next != "" {
+		w.Header().Set("X-Next-Cursor", next)
+	}
+	if err := geoformat.Write(w, f, title, features); err != nil {
+		log.Printf("Writing %s failed: %v", f.MediaType(), err)
+	}
 }
@@ -0,0 +1,486 @@
+package useractions
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"example.com/m/apis/findplaces"
+	"example.com/m/db"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	maxItineraryDays = 30
+	maxDayStops      = 50
+)
+
+var (
+	ErrItineraryNotFound = errors.New("itinerary not found")
+	ErrStopNotFound      = errors.New("stop not found")
+	ErrNotSaved          = errors.New("stops must be saved places")
+	ErrDayOutOfRange     = errors.New("day is outside the itinerary")
+	ErrDayFull           = errors.New("day has too many stops")
+	ErrDaysInUse         = errors.New("later days still have stops")
+	ErrBadStopOrder      = errors.New("order must list every stop on the day exactly once")
+)
+
+type Itinerary struct {
+	ID int64 `json:"id"`
+	// StartDate is YYYY-MM-DD; without it days are only numbered.
+	StartDate *string   `json:"start_date,omitempty" example:"2026-12-24"`
+	Name      string    `json:"name"`
+	Days      int       `json:"days"`
+	StopCount int       `json:"stop_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Plan      []Day     `json:"plan,omitempty"`
+}
+
+// Day is one day of an itinerary, its stops in visiting order.
+type Day struct {
+	Day   int     `json:"day"`
+	Date  *string `json:"date,omitempty" example:"2026-12-24"`
+	Stops []Stop  `json:"stops"`
+	// DistanceMeters is the straight-line length of the day's route.
+	DistanceMeters float64 `json:"distance_meters"`
+}
+
+type Stop struct {
+	PlaceSummary
+	ID       int64 `json:"id"`
+	Day      int   `json:"day"`
+	Position int   `json:"position"`
+	// PlannedTime is HH:MM local time.
+	PlannedTime     *string `json:"planned_time,omitempty" example:"09:30"`
+	DurationMinutes *int    `json:"duration_minutes,omitempty"`
+	Note            string  `json:"note"`
+	// DistanceFromPreviousMeters is how far this stop is from the one
+	// before it; unset on a day's first stop and on stops with no location.
+	DistanceFromPreviousMeters *float64 `json:"distance_from_previous_meters,omitempty"`
+}
+
+// StopChanges are the stop fields to update; nil leaves a field as it is,
+// and an empty PlannedTime clears it.
+type StopChanges struct {
+	Day             *int
+	PlannedTime     *string
+	DurationMinutes *int
+	Note            *string
+}
+
+func (s Stop) located() bool {
+	return s.Lat != 0 || s.Lon != 0
+}
+
+// measure fills in the distances between consecutive stops.
+func (d *Day) measure() {
+	d.DistanceMeters = 0
+	for i := range d.Stops {
+		d.Stops[i].DistanceFromPreviousMeters = nil
+		if i == 0 || !d.Stops[i].located() || !d.Stops[i-1].located() {
+			continue
+		}
+		a, b := d.Stops[i-1], d.Stops[i]
+		m := math.Round(findplaces.Haversine(a.Lat, a.Lon, b.Lat, b.Lon))
+		d.Stops[i].DistanceFromPreviousMeters = &m
+		d.DistanceMeters += m
+	}
+}
+
+const itineraryColumns = `i.id, i.name, to_char(i.start_date, 'YYYY-MM-DD'), i.days,
+	i.created_at, i.updated_at,
+	(SELECT COUNT(*) FROM itinerary_stops s WHERE s.itinerary_id = i.id)`
+
+func itineraryDest(it *Itinerary) []any {
+	return []any{&it.ID, &it.Name, &it.StartDate, &it.Days, &it.CreatedAt, &it.UpdatedAt, &it.StopCount}
+}
+
+func ListItineraries(ctx context.Context, userUUID string) ([]Itinerary, error) {
+	rows, err := db.Conn.Query(ctx,
+		`SELECT `+itineraryColumns+` FROM itineraries i
+		 WHERE i.owner_uuid = $1
+		 ORDER BY i.start_date DESC NULLS LAST, i.updated_at DESC`,
+		userUUID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []Itinerary{}
+	for rows.Next() {
+		var it Itinerary
+		if err := rows.Scan(itineraryDest(&it)...); err != nil {
+			return nil, err
+		}
+		list = append(list, it)
+	}
+	return list, rows.Err()
+}
+
+func CreateItinerary(ctx context.Context, userUUID, name string, startDate *string, days int) (Itinerary, error) {
+	var it Itinerary
+	err := db.Conn.QueryRow(ctx,
+		`INSERT INTO itineraries AS i (owner_uuid, name, start_date, days) VALUES ($1, $2, $3::date, $4)
+		 RETURNING `+itineraryColumns,
+		userUUID, name, startDate, days,
+	).Scan(itineraryDest(&it)...)
+	return it, err
+}
+
+// ownItinerary returns the itinerary if userUUID owns it.
+func ownItinerary(ctx context.Context, userUUID string, id int64) (Itinerary, error) {
+	var it Itinerary
+	err := db.Conn.QueryRow(ctx,
+		`SELECT `+itineraryColumns+` FROM itineraries i WHERE i.id = $1 AND i.owner_uuid = $2`,
+		id, userUUID,
+	).Scan(itineraryDest(&it)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Itinerary{}, ErrItineraryNotFound
+	}
+	return it, err
+}
+
+// GetItinerary returns an itinerary with its stops laid out by day.
+func GetItinerary(ctx context.Context, userUUID string, id int64) (Itinerary, error) {
+	it, err := ownItinerary(ctx, userUUID, id)
+	if err != nil {
+		return Itinerary{}, err
+	}
+
+	stops, err := itineraryStops(ctx, id, 0)
+	if err != nil {
+		return Itinerary{}, err
+	}
+
+	var start time.Time
+	if it.StartDate != nil {
+		start, _ = time.Parse(time.DateOnly, *it.StartDate)
+	}
+	it.Plan = make([]Day, it.Days)
+	for i := range it.Plan {
+		it.Plan[i] = Day{Day: i + 1, Stops: []Stop{}}
+		if it.StartDate != nil {
+			date := start.AddDate(0, 0, i).Format(time.DateOnly)
+			it.Plan[i].Date = &date
+		}
+	}
+	for _, s := range stops {
+		if s.Day >= 1 && s.Day <= it.Days {
+			it.Plan[s.Day-1].Stops = append(it.Plan[s.Day-1].Stops, s)
+		}
+	}
+	for i := range it.Plan {
+		it.Plan[i].measure()
+	}
+	return it, nil
+}
+
+// itineraryStops returns the stops of one day, or of every day when day is
+// 0, in order.
+func itineraryStops(ctx context.Context, id int64, day int) ([]Stop, error) {
+	rows, err := db.Conn.Query(ctx,
+		`SELECT t.id, t.place_id, t.day, t.position, to_char(t.planned_time, 'HH24:MI'),
+			t.duration_minutes, t.note, `+summaryColumns+`
+		 FROM itinerary_stops t LEFT JOIN places p ON p.place_id = t.place_id
+		 WHERE t.itinerary_id = $1 AND ($2 = 0 OR t.day = $2)
+		 ORDER BY t.day, t.position, t.id`,
+		id, day,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stops := []Stop{}
+	var missing []string
+	for rows.Next() {
+		var s Stop
+		var found bool
+		dest := append([]any{&s.ID, &s.PlaceID, &s.Day, &s.Position, &s.PlannedTime, &s.DurationMinutes, &s.Note},
+			summaryDest(&s.PlaceSummary, &found)...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		if !found {
+			missing = append(missing, s.PlaceID)
+		}
+		stops = append(stops, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	backfillLater(ctx, missing)
+	return stops, nil
+}
+
+// UpdateItinerary changes an itinerary's name, start date or length. An
+// empty startDate clears it. Days can't shrink past a day that has stops.
+func UpdateItinerary(ctx context.Context, userUUID string, id int64, name, startDate *string, days *int) error {
+	if _, err := ownItinerary(ctx, userUUID, id); err != nil {
+		return err
+	}
+
+	if days != nil {
+		var inUse bool
+		err := db.Conn.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM itinerary_stops WHERE itinerary_id = $1 AND day > $2)`,
+			id, *days,
+		).Scan(&inUse)
+		if err != nil {
+			return err
+		}
+		if inUse {
+			return ErrDaysInUse
+		}
+	}
+
+	_, err := db.Conn.Exec(ctx,
+		`UPDATE itineraries SET name = COALESCE($2, name),
+			start_date = CASE WHEN $3::text IS NULL THEN start_date
+				WHEN $3 = '' THEN NULL ELSE $3::date END,
+			days = COALESCE($4, days),
+			updated_at = NOW()
+		 WHERE id = $1`,
+		id, name, startDate, days,
+	)
+	return err
+}
+
+func DeleteItinerary(ctx context.Context, userUUID string, id int64) error {
+	if _, err := ownItinerary(ctx, userUUID, id); err != nil {
+		return err
+	}
+	_, err := db.Conn.Exec(ctx, `DELETE FROM itineraries WHERE id = $1`, id)
+	return err
+}
+
+func touchItinerary(ctx context.Context, id int64) {
+	db.Conn.Exec(ctx, `UPDATE itineraries SET updated_at = NOW() WHERE id = $1`, id)
+}
+
+// checkDay makes sure day exists and still has room for another stop.
+func checkDay(ctx context.Context, it Itinerary, day int) error {
+	if day < 1 || day > it.Days {
+		return ErrDayOutOfRange
+	}
+	var n int
+	err := db.Conn.QueryRow(ctx,
+		`SELECT COUNT(*) FROM itinerary_stops WHERE itinerary_id = $1 AND day = $2`, it.ID, day,
+	).Scan(&n)
+	if err == nil && n >= maxDayStops {
+		return ErrDayFull
+	}
+	return err
+}
+
+// AddStop appends one of the user's saved places to the end of a day.
+func AddStop(ctx context.Context, userUUID string, id int64, placeID string, day int, plannedTime *string, durationMinutes *int, note string) (int64, error) {
+	it, err := ownItinerary(ctx, userUUID, id)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkDay(ctx, it, day); err != nil {
+		return 0, err
+	}
+
+	var saved bool
+	err = db.Conn.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM user_saved_places WHERE user_uuid = $1 AND place_id = $2)`,
+		userUUID, placeID,
+	).Scan(&saved)
+	if err != nil {
+		return 0, err
+	}
+	if !saved {
+		return 0, ErrNotSaved
+	}
+
+	var stopID int64
+	err = db.Conn.QueryRow(ctx,
+		`INSERT INTO itinerary_stops (itinerary_id, day, position, place_id, planned_time, duration_minutes, note)
+		 SELECT $1, $2, COALESCE(MAX(position) + 1, 0), $3, NULLIF($4, '')::time, $5, $6
+		 FROM itinerary_stops WHERE itinerary_id = $1 AND day = $2
+		 RETURNING id`,
+		id, day, placeID, plannedTime, durationMinutes, note,
+	).Scan(&stopID)
+	if err != nil {
+		return 0, err
+	}
+	touchItinerary(ctx, id)
+	return stopID, nil
+}
+
+// UpdateStop changes a stop. Moving it to another day puts it at the end of
+// that day.
+func UpdateStop(ctx context.Context, userUUID string, id, stopID int64, c StopChanges) error {
+	it, err := ownItinerary(ctx, userUUID, id)
+	if err != nil {
+		return err
+	}
+
+	var day int
+	err = db.Conn.QueryRow(ctx,
+		`SELECT day FROM itinerary_stops WHERE id = $1 AND itinerary_id = $2`, stopID, id,
+	).Scan(&day)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrStopNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	position := -1
+	if c.Day != nil && *c.Day != day {
+		if err := checkDay(ctx, it, *c.Day); err != nil {
+			return err
+		}
+		err := db.Conn.QueryRow(ctx,
+			`SELECT COALESCE(MAX(position) + 1, 0) FROM itinerary_stops WHERE itinerary_id = $1 AND day = $2`,
+			id, *c.Day,
+		).Scan(&position)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = db.Conn.Exec(ctx,
+		`UPDATE itinerary_stops SET day = COALESCE($2, day),
+			position = CASE WHEN $3 < 0 THEN position ELSE $3 END,
+			planned_time = CASE WHEN $4::text IS NULL THEN planned_time
+				WHEN $4 = '' THEN NULL ELSE $4::time END,
+			duration_minutes = COALESCE($5, duration_minutes),
+			note = COALESCE($6, note)
+		 WHERE id = $1`,
+		stopID, c.Day, position, c.PlannedTime, c.DurationMinutes, c.Note,
+	)
+	if err != nil {
+		return err
+	}
+	touchItinerary(ctx, id)
+	return nil
+}
+
+func RemoveStop(ctx context.Context, userUUID string, id, stopID int64) error {
+	if _, err := ownItinerary(ctx, userUUID, id); err != nil {
+		return err
+	}
+	tag, err := db.Conn.Exec(ctx,
+		`DELETE FROM itinerary_stops WHERE id = $1 AND itinerary_id = $2`, stopID, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrStopNotFound
+	}
+	touchItinerary(ctx, id)
+	return nil
+}
+
+func setStopOrder(ctx context.Context, id int64, stopIDs []int64) error {
+	_, err := db.Conn.Exec(ctx,
+		`UPDATE itinerary_stops s SET position = o.n - 1
+		 FROM unnest($2::bigint[]) WITH ORDINALITY AS o(id, n)
+		 WHERE s.itinerary_id = $1 AND s.id = o.id`,
+		id, stopIDs,
+	)
+	if err == nil {
+		touchItinerary(ctx, id)
+	}
+	return err
+}
+
+// ReorderDay puts a day's stops in the order given, which must name each of
+// them exactly once.
+func ReorderDay(ctx context.Context, userUUID string, id int64, day int, stopIDs []int64) error {
+	it, err := ownItinerary(ctx, userUUID, id)
+	if err != nil {
+		return err
+	}
+	if day < 1 || day > it.Days {
+		return ErrDayOutOfRange
+	}
+
+	rows, err := db.Conn.Query(ctx,
+		`SELECT id FROM itinerary_stops WHERE itinerary_id = $1 AND day = $2`, id, day)
+	if err != nil {
+		return err
+	}
+	current, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return err
+	}
+
+	if !isPermutation(current, stopIDs) {
+		return ErrBadStopOrder
+	}
+	return setStopOrder(ctx, id, stopIDs)
+}
+
+// OptimizeDay orders a day's stops to shorten the route between them, and
+// saves that order when apply is set. Stops with no known location keep
+// their relative order at the end. It also returns the route's length
+// before optimizing.
+func OptimizeDay(ctx context.Context, userUUID string, id int64, day int, fixStart, apply bool) (Day, float64, error) {
+	it, err := ownItinerary(ctx, userUUID, id)
+	if err != nil {
+		return Day{}, 0, err
+	}
+	if day < 1 || day > it.Days {
+		return Day{}, 0, ErrDayOutOfRange
+	}
+
+	stops, err := itineraryStops(ctx, id, day)
+	if err != nil {
+		return Day{}, 0, err
+	}
+	before := Day{Day: day, Stops: stops}
+	before.measure()
+
+	var located, unlocated []Stop
+	for _, s := range stops {
+		if s.located() {
+			located = append(located, s)
+		} else {
+			unlocated = append(unlocated, s)
+		}
+	}
+	// The fixed start is the day's first stop, which only means something
+	// when that stop has a location.
+	fixStart = fixStart && len(stops) > 0 && stops[0].located()
+
+	points := make([]Point, len(located))
+	for i, s := range located {
+		points[i] = Point{Lat: s.Lat, Lon: s.Lon}
+	}
+	ordered := make([]Stop, 0, len(stops))
+	for _, i := range OrderStops(points, fixStart) {
+		ordered = append(ordered, located[i])
+	}
+	ordered = append(ordered, unlocated...)
+
+	ids := make([]int64, len(ordered))
+	for i := range ordered {
+		ordered[i].Position = i
+		ids[i] = ordered[i].ID
+	}
+	after := Day{Day: day, Stops: ordered}
+	if it.StartDate != nil {
+		if start, err := time.Parse(time.DateOnly, *it.StartDate); err == nil {
+			date := start.AddDate(0, 0, day-1).Format(time.DateOnly)
+			after.Date = &date
+		}
+	}
+	after.measure()
+
+	if apply {
+		if err := setStopOrder(ctx, id, ids); err != nil {
+			return Day{}, 0, err
+		}
+	}
+	return after, before.DistanceMeters, nil
+}
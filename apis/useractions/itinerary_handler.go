@@ -0,0 +1,536 @@
+package useractions
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"example.com/m/i18n"
+	"example.com/m/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+type ItineraryRequest struct {
+	Name *string `json:"name"`
+	// StartDate is YYYY-MM-DD; an empty string clears it.
+	StartDate *string `json:"start_date" example:"2026-12-24"`
+	Days      *int    `json:"days" example:"2"`
+}
+
+// valid checks the fields that are set.
+func (req ItineraryRequest) valid() bool {
+	if req.Name != nil {
+		*req.Name = strings.TrimSpace(*req.Name)
+		if n := utf8.RuneCountInString(*req.Name); n == 0 || n > maxCollectionName {
+			return false
+		}
+	}
+	if req.StartDate != nil && *req.StartDate != "" {
+		if _, err := time.Parse(time.DateOnly, *req.StartDate); err != nil {
+			return false
+		}
+	}
+	return req.Days == nil || (*req.Days >= 1 && *req.Days <= maxItineraryDays)
+}
+
+// StopRequest adds or changes a stop. place_id is only read when adding; day
+// defaults to 1 there. An empty planned_time clears it.
+type StopRequest struct {
+	PlaceID         string  `json:"place_id,omitempty"`
+	Day             *int    `json:"day" example:"1"`
+	PlannedTime     *string `json:"planned_time" example:"09:30"`
+	DurationMinutes *int    `json:"duration_minutes" example:"60"`
+	Note            *string `json:"note"`
+}
+
+func (req StopRequest) valid() bool {
+	if req.PlannedTime != nil && *req.PlannedTime != "" {
+		if _, err := time.Parse("15:04", *req.PlannedTime); err != nil {
+			return false
+		}
+	}
+	if req.DurationMinutes != nil && (*req.DurationMinutes < 0 || *req.DurationMinutes > 24*60) {
+		return false
+	}
+	return req.Note == nil || utf8.RuneCountInString(*req.Note) <= maxCollectionText
+}
+
+type StopOrderRequest struct {
+	StopIDs []int64 `json:"stop_ids"`
+}
+
+type ItinerariesResponse struct {
+	Itineraries []Itinerary `json:"itineraries"`
+}
+
+type OptimizeResponse struct {
+	Day                    Day     `json:"day"`
+	PreviousDistanceMeters float64 `json:"previous_distance_meters"`
+	SavedMeters            float64 `json:"saved_meters"`
+	Applied                bool    `json:"applied"`
+}
+
+// itineraryParams reads the itinerary id and, when the route has one, the
+// stop id or day from the path.
+func itineraryParams(r *http.Request, name string) (id, sub int64, ok bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, 0, false
+	}
+	if name == "" {
+		return id, 0, true
+	}
+	sub, err = strconv.ParseInt(chi.URLParam(r, name), 10, 64)
+	return id, sub, err == nil
+}
+
+func itineraryError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrItineraryNotFound):
+		i18n.Error(w, r, i18n.ItineraryNotFound, http.StatusNotFound)
+	case errors.Is(err, ErrStopNotFound):
+		i18n.Error(w, r, i18n.StopNotFound, http.StatusNotFound)
+	case errors.Is(err, ErrNotSaved):
+		i18n.Error(w, r, i18n.PlaceNotSaved, http.StatusBadRequest)
+	case errors.Is(err, ErrDayOutOfRange):
+		i18n.Error(w, r, i18n.InvalidDay, http.StatusBadRequest)
+	case errors.Is(err, ErrDayFull):
+		i18n.Error(w, r, i18n.DayFull, http.StatusBadRequest)
+	case errors.Is(err, ErrDaysInUse):
+		i18n.Error(w, r, i18n.DaysInUse, http.StatusConflict)
+	case errors.Is(err, ErrBadStopOrder):
+		i18n.Error(w, r, i18n.InvalidStopOrder, http.StatusBadRequest)
+	default:
+		log.Printf("Itinerary update failed: %v", err)
+		i18n.Error(w, r, i18n.ItineraryFailed, http.StatusInternalServerError)
+	}
+}
+
+// ListItinerariesHandler lists the user's itineraries
+// @Summary List Itineraries
+// @Description The caller's itineraries, without their stops, latest trips first
+// @Tags itineraries
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} ItinerariesResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/itineraries [get]
+func ListItinerariesHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	list, err := ListItineraries(r.Context(), userUUID)
+	if err != nil {
+		itineraryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ItinerariesResponse{Itineraries: list})
+}
+
+// CreateItineraryHandler creates an itinerary
+// @Summary Create Itinerary
+// @Description Start an empty trip plan with a name, an optional start date and a number of days (default 1, at most 30)
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ItineraryRequest true "Itinerary"
+// @Success 201 {object} Itinerary
+// @Failure 400 {object} map[string]string "Invalid itinerary"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/itineraries [post]
+func CreateItineraryHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	var req ItineraryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == nil || !req.valid() {
+		i18n.Error(w, r, i18n.InvalidItinerary, http.StatusBadRequest)
+		return
+	}
+	days := 1
+	if req.Days != nil {
+		days = *req.Days
+	}
+	if req.StartDate != nil && *req.StartDate == "" {
+		req.StartDate = nil
+	}
+
+	it, err := CreateItinerary(r.Context(), userUUID, *req.Name, req.StartDate, days)
+	if err != nil {
+		itineraryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(it)
+}
+
+// GetItineraryHandler returns an itinerary day by day
+// @Summary Get Itinerary
+// @Description An itinerary with its stops grouped by day in visiting order, and the straight-line distance between consecutive stops and over each day
+// @Tags itineraries
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param Accept-Language header string false "Preferred languages for place names and messages, e.g. or, hi;q=0.8"
+// @Success 200 {object} Itinerary
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Itinerary not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/itineraries/{id} [get]
+func GetItineraryHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, _, ok := itineraryParams(r, "")
+	if !ok {
+		i18n.Error(w, r, i18n.ItineraryNotFound, http.StatusNotFound)
+		return
+	}
+
+	it, err := GetItinerary(r.Context(), userUUID, id)
+	if err != nil {
+		itineraryError(w, r, err)
+		return
+	}
+
+	langs := i18n.Languages(r)
+	for d := range it.Plan {
+		for i := range it.Plan[d].Stops {
+			it.Plan[d].Stops[i].localize(langs)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
+	json.NewEncoder(w).Encode(it)
+}
+
+// UpdateItineraryHandler changes an itinerary
+// @Summary Update Itinerary
+// @Description Change an itinerary's name, start date or number of days. Omitted fields are left as they are; an empty start_date clears it. Days can't be dropped while they still have stops.
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param request body ItineraryRequest true "Fields to change"
+// @Success 200 {object} map[string]string "status: updated"
+// @Failure 400 {object} map[string]string "Invalid itinerary"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Itinerary not found"
+// @Failure 409 {object} map[string]string "Dropped days still have stops"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/itineraries/{id} [patch]
+func UpdateItineraryHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, _, ok := itineraryParams(r, "")
+	if !ok {
+		i18n.Error(w, r, i18n.ItineraryNotFound, http.StatusNotFound)
+		return
+	}
+
+	var req ItineraryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !req.valid() {
+		i18n.Error(w, r, i18n.InvalidItinerary, http.StatusBadRequest)
+		return
+	}
+
+	if err := UpdateItinerary(r.Context(), userUUID, id, req.Name, req.StartDate, req.Days); err != nil {
+		itineraryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
+}
+
+// DeleteItineraryHandler deletes an itinerary
+// @Summary Delete Itinerary
+// @Description Delete an itinerary and its stops. The places stay saved.
+// @Tags itineraries
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Success 200 {object} map[string]string "status: deleted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Itinerary not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/itineraries/{id} [delete]
+func DeleteItineraryHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, _, ok := itineraryParams(r, "")
+	if !ok {
+		i18n.Error(w, r, i18n.ItineraryNotFound, http.StatusNotFound)
+		return
+	}
+
+	if err := DeleteItinerary(r.Context(), userUUID, id); err != nil {
+		itineraryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "deleted"})
+}
+
+// AddStopHandler adds a saved place to an itinerary
+// @Summary Add Stop
+// @Description Add one of the caller's saved places to the end of a day (default day 1), with an optional planned start time, duration and note. A day holds at most 50 stops.
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param request body StopRequest true "Stop"
+// @Success 201 {object} map[string]interface{} "status: added, stop_id"
+// @Failure 400 {object} map[string]string "Invalid stop, day, or place not saved"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Itinerary not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/itineraries/{id}/stops [post]
+func AddStopHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, _, ok := itineraryParams(r, "")
+	if !ok {
+		i18n.Error(w, r, i18n.ItineraryNotFound, http.StatusNotFound)
+		return
+	}
+
+	var req StopRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.PlaceID == "" {
+		i18n.Error(w, r, i18n.InvalidPlaceID, http.StatusBadRequest)
+		return
+	}
+	if !req.valid() {
+		i18n.Error(w, r, i18n.InvalidItinerary, http.StatusBadRequest)
+		return
+	}
+	day := 1
+	if req.Day != nil {
+		day = *req.Day
+	}
+	var note string
+	if req.Note != nil {
+		note = *req.Note
+	}
+
+	stopID, err := AddStop(r.Context(), userUUID, id, req.PlaceID, day, req.PlannedTime, req.DurationMinutes, note)
+	if err != nil {
+		itineraryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{"status": "added", "stop_id": stopID})
+}
+
+// UpdateStopHandler changes a stop
+// @Summary Update Stop
+// @Description Change a stop's day, planned time, duration or note. Omitted fields are left as they are; an empty planned_time clears it. A stop moved to another day goes to the end of that day.
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param stop_id path int true "Stop ID"
+// @Param request body StopRequest true "Fields to change"
+// @Success 200 {object} map[string]string "status: updated"
+// @Failure 400 {object} map[string]string "Invalid stop or day"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Itinerary or stop not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/itineraries/{id}/stops/{stop_id} [patch]
+func UpdateStopHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, stopID, ok := itineraryParams(r, "stop_id")
+	if !ok {
+		i18n.Error(w, r, i18n.StopNotFound, http.StatusNotFound)
+		return
+	}
+
+	var req StopRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || !req.valid() {
+		i18n.Error(w, r, i18n.InvalidItinerary, http.StatusBadRequest)
+		return
+	}
+
+	changes := StopChanges{Day: req.Day, PlannedTime: req.PlannedTime, DurationMinutes: req.DurationMinutes, Note: req.Note}
+	if err := UpdateStop(r.Context(), userUUID, id, stopID, changes); err != nil {
+		itineraryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
+}
+
+// RemoveStopHandler removes a stop
+// @Summary Remove Stop
+// @Description Remove a stop from an itinerary. The place stays saved.
+// @Tags itineraries
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param stop_id path int true "Stop ID"
+// @Success 200 {object} map[string]string "status: removed"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Itinerary or stop not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/itineraries/{id}/stops/{stop_id} [delete]
+func RemoveStopHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, stopID, ok := itineraryParams(r, "stop_id")
+	if !ok {
+		i18n.Error(w, r, i18n.StopNotFound, http.StatusNotFound)
+		return
+	}
+
+	if err := RemoveStop(r.Context(), userUUID, id, stopID); err != nil {
+		itineraryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "removed"})
+}
+
+// ReorderDayHandler reorders a day's stops
+// @Summary Reorder Day
+// @Description Set the visiting order of a day's stops. stop_ids must list every stop on the day exactly once.
+// @Tags itineraries
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param day path int true "Day number, from 1"
+// @Param request body StopOrderRequest true "Stop IDs in their new order"
+// @Success 200 {object} map[string]string "status: reordered"
+// @Failure 400 {object} map[string]string "Invalid day or order"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Itinerary not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/itineraries/{id}/days/{day}/order [put]
+func ReorderDayHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, day, ok := itineraryParams(r, "day")
+	if !ok {
+		i18n.Error(w, r, i18n.InvalidDay, http.StatusBadRequest)
+		return
+	}
+
+	var req StopOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.StopIDs == nil {
+		i18n.Error(w, r, i18n.InvalidStopOrder, http.StatusBadRequest)
+		return
+	}
+
+	if err := ReorderDay(r.Context(), userUUID, id, int(day), req.StopIDs); err != nil {
+		itineraryError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "reordered"})
+}
+
+// OptimizeDayHandler orders a day's stops for the shortest route
+// @Summary Optimize Day
+// @Description Order a day's stops to minimise straight-line travel between them (nearest neighbour, then 2-opt), returning the new order with the distance between stops. With fix_start the day's current first stop stays first. apply=false previews without saving. Planned times are left as they are. Stops whose location is unknown go last.
+// @Tags itineraries
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Itinerary ID"
+// @Param day path int true "Day number, from 1"
+// @Param fix_start query bool false "Keep the first stop first"
+// @Param apply query bool false "Save the new order (default true)"
+// @Param Accept-Language header string false "Preferred languages for place names and messages, e.g. or, hi;q=0.8"
+// @Success 200 {object} OptimizeResponse
+// @Failure 400 {object} map[string]string "Invalid day"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Itinerary not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/itineraries/{id}/days/{day}/optimize [post]
+func OptimizeDayHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+	id, day, ok := itineraryParams(r, "day")
+	if !ok {
+		i18n.Error(w, r, i18n.InvalidDay, http.StatusBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	fixStart, _ := strconv.ParseBool(q.Get("fix_start"))
+	apply := true
+	if v := q.Get("apply"); v != "" {
+		apply, _ = strconv.ParseBool(v)
+	}
+
+	d, before, err := OptimizeDay(r.Context(), userUUID, id, int(day), fixStart, apply)
+	if err != nil {
+		itineraryError(w, r, err)
+		return
+	}
+
+	langs := i18n.Languages(r)
+	for i := range d.Stops {
+		d.Stops[i].localize(langs)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Vary", "Accept-Language")
+	json.NewEncoder(w).Encode(OptimizeResponse{
+		Day:                    d,
+		PreviousDistanceMeters: before,
+		SavedMeters:            math.Max(before-d.DistanceMeters, 0),
+		Applied:                apply,
+	})
+}
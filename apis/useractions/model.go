@@ -2,8 +2,18 @@ package useractions
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
 
+	"example.com/m/apis/findplaces"
 	"example.com/m/db"
+	"example.com/m/events"
+	"example.com/m/geoformat"
+	"example.com/m/pagination"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 func SavePlace(ctx context.Context, userUUID, placeID string) error {
@@ -12,7 +22,12 @@ func SavePlace(ctx context.Context, userUUID, placeID string) error {
 		 VALUES ($1, $2) ON CONFLICT (user_uuid, place_id) DO NOTHING`,
 		userUUID, placeID,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	events.Publish(ctx, events.Event{Kind: events.Save, UserUUID: userUUID, PlaceID: placeID})
+	return nil
 }
 
 func RemoveSavedPlace(ctx context.Context, userUUID, placeID string) error {
@@ -24,53 +39,277 @@ func RemoveSavedPlace(ctx context.Context, userUUID, placeID string) error {
 	return err
 }
 
-func GetSavedPlaces(ctx context.Context, userUUID string) ([]string, error) {
-	rows, err := db.Conn.Query(ctx,
-		`SELECT place_id FROM user_saved_places
-		 WHERE user_uuid=$1 ORDER BY saved_at DESC`,
-		userUUID,
-	)
+// PlaceSummary is what list endpoints show for a place, joined from the
+// places catalog.
+type PlaceSummary struct {
+	PlaceID    string   `json:"place_id"`
+	Name       string   `json:"name"`
+	Lat        float64  `json:"lat"`
+	Lon        float64  `json:"lon"`
+	Formatted  string   `json:"formatted"`
+	City       string   `json:"city"`
+	State      string   `json:"state"`
+	Country    string   `json:"country"`
+	Categories []string `json:"categories"`
+	// Names holds the place's name per language code, where known.
+	Names map[string]string `json:"names,omitempty"`
+}
+
+// localize replaces Name with the caller's preferred language, when known.
+func (s *PlaceSummary) localize(langs []string) {
+	s.Name = findplaces.LocalName(s.Names, s.Name, langs)
+}
+
+// Feature renders the summary for GeoJSON/GPX/KML output, stamped with when
+// it was saved or visited and carrying record as its properties.
+func (s PlaceSummary) Feature(at *time.Time, record any) geoformat.Feature {
+	return geoformat.Feature{
+		ID:          s.PlaceID,
+		Name:        s.Name,
+		Lat:         s.Lat,
+		Lon:         s.Lon,
+		Description: s.Formatted,
+		Categories:  s.Categories,
+		Time:        at,
+		Properties:  record,
+	}
+}
+
+type SavedPlace struct {
+	PlaceSummary
+	SavedAt time.Time `json:"saved_at"`
+}
+
+type Visit struct {
+	PlaceSummary
+	VisitedAt time.Time `json:"visited_at"`
+	Verified  bool      `json:"verified"`
+}
+
+// summaryColumns selects a PlaceSummary from a LEFT JOIN on places aliased p,
+// plus whether the catalog row exists.
+const summaryColumns = `p.place_id IS NOT NULL, COALESCE(p.name, ''),
+	COALESCE(p.latitude, 0), COALESCE(p.longitude, 0), COALESCE(p.formatted, ''),
+	COALESCE(p.city, ''), COALESCE(p.state, ''), COALESCE(p.country, ''),
+	COALESCE(p.categories, '{}'), COALESCE(p.names, '{}')`
+
+func summaryDest(s *PlaceSummary, found *bool) []any {
+	return []any{found, &s.Name, &s.Lat, &s.Lon, &s.Formatted, &s.City, &s.State, &s.Country, &s.Categories, &s.Names}
+}
+
+// backfilling holds the place_ids with a backfill queued or running, so
+// loading a list again doesn't look the same places up twice.
+var backfilling sync.Map
+
+// backfillTimeout bounds one background lookup, retries included.
+const backfillTimeout = 20 * time.Second
+
+// backfillLater resolves places that predate the catalog through the
+// provider in the background, one at a time, so a list isn't held up by an
+// upstream call per miss. Until then they are listed with just their
+// place_id.
+func backfillLater(ctx context.Context, placeIDs []string) {
+	var queued []string
+	for _, id := range placeIDs {
+		if _, busy := backfilling.LoadOrStore(id, true); !busy {
+			queued = append(queued, id)
+		}
+	}
+	if len(queued) == 0 {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		for _, id := range queued {
+			lookupCtx, cancel := context.WithTimeout(ctx, backfillTimeout)
+			if _, err := findplaces.LookupPlace(lookupCtx, db.Conn, findplaces.DefaultProvider(), id); err != nil {
+				log.Printf("Backfilling place %s failed: %v", id, err)
+			}
+			cancel()
+			backfilling.Delete(id)
+		}
+	}()
+}
+
+// listRow is one row of a user's place list (saved places or visits).
+type listRow struct {
+	PlaceSummary
+	At   time.Time
+	Flag bool
+}
+
+// listPlaces pages through table (user_saved_places or user_visit_history)
+// for a user, joined to the catalog and ordered by timeCol. flag is a boolean
+// expression copied into listRow.Flag. category matches a Geoapify category
+// or any of its subcategories ("tourism" matches "tourism.sights").
+func listPlaces(ctx context.Context, table, timeCol, flag, userUUID string, page pagination.Page, category string) ([]listRow, string, error) {
+	args := []any{userUUID}
+	query := `SELECT t.place_id, t.` + timeCol + `, ` + flag + `, ` + summaryColumns + `
+		 FROM ` + table + ` t LEFT JOIN places p ON p.place_id = t.place_id
+		 WHERE t.user_uuid=$1` + page.Where("t."+timeCol, "t.place_id", &args)
+
+	if category != "" {
+		args = append(args, category)
+		query += fmt.Sprintf(
+			` AND EXISTS (SELECT 1 FROM unnest(p.categories) c WHERE c = $%[1]d OR c LIKE $%[1]d || '.%%')`,
+			len(args))
+	}
+	query += page.OrderBy("t."+timeCol, "t.place_id")
+
+	rows, err := db.Conn.Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer rows.Close()
 
-	var places []string
+	list := make([]listRow, 0)
+	var missing []int
 	for rows.Next() {
-		var pid string
-		if err := rows.Scan(&pid); err == nil {
-			places = append(places, pid)
+		var row listRow
+		var found bool
+		dest := append([]any{&row.PlaceID, &row.At, &row.Flag}, summaryDest(&row.PlaceSummary, &found)...)
+		if err := rows.Scan(dest...); err == nil {
+			if !found {
+				missing = append(missing, len(list))
+			}
+			list = append(list, row)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	list, next := pagination.Trim(page, list, func(r listRow) (time.Time, string) {
+		return r.At, r.PlaceID
+	})
+
+	var missingIDs []string
+	for _, i := range missing {
+		if i < len(list) {
+			missingIDs = append(missingIDs, list[i].PlaceID)
 		}
 	}
-	return places, nil
+	backfillLater(ctx, missingIDs)
+	return list, next, nil
 }
 
-func LogVisit(ctx context.Context, userUUID, placeID string) error {
-	_, err := db.Conn.Exec(ctx,
-		`INSERT INTO user_visit_history (user_uuid, place_id)
-		 VALUES ($1, $2)`,
-		userUUID, placeID,
+func GetSavedPlaces(ctx context.Context, userUUID string, page pagination.Page, category string) ([]SavedPlace, string, error) {
+	list, next, err := listPlaces(ctx, "user_saved_places", "saved_at", "FALSE", userUUID, page, category)
+	if err != nil {
+		return nil, "", err
+	}
+
+	places := make([]SavedPlace, len(list))
+	for i, row := range list {
+		places[i] = SavedPlace{PlaceSummary: row.PlaceSummary, SavedAt: row.At}
+	}
+	return places, next, nil
+}
+
+// ErrRecentVisit is returned by LogVisitOnce when the user already has a
+// visit to the place by the same method within the cooldown.
+var ErrRecentVisit = errors.New("place visited too recently")
+
+// LogVisit records a visit along with where the user was (nil when the
+// client didn't say) and how verification went.
+func LogVisit(ctx context.Context, userUUID, placeID string, at *Location, check VisitCheck) error {
+	if err := insertVisit(ctx, db.Conn, userUUID, placeID, at, check); err != nil {
+		return err
+	}
+	publishVisit(ctx, userUUID, placeID, check)
+	return nil
+}
+
+// LogVisitOnce is LogVisit with a cooldown: it records nothing and returns
+// ErrRecentVisit if the user visited the place by check.Method within
+// cooldown. The check and the insert run in one transaction holding a lock
+// on the user and place, so two quick requests can't both get through.
+func LogVisitOnce(ctx context.Context, userUUID, placeID string, at *Location, check VisitCheck, cooldown time.Duration) error {
+	tx, err := db.Conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`SELECT pg_advisory_xact_lock(hashtext('visit|' || $1 || '|' || $2))`, userUUID, placeID,
+	); err != nil {
+		return err
+	}
+
+	var recent bool
+	err = tx.QueryRow(ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM user_visit_history
+			WHERE user_uuid=$1 AND place_id=$2 AND verification_method=$3
+			  AND visited_at > $4)`,
+		userUUID, placeID, check.Method, time.Now().Add(-cooldown),
+	).Scan(&recent)
+	if err != nil {
+		return err
+	}
+	if recent {
+		return ErrRecentVisit
+	}
+
+	if err := insertVisit(ctx, tx, userUUID, placeID, at, check); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	publishVisit(ctx, userUUID, placeID, check)
+	return nil
+}
+
+// execer is what insertVisit needs from the pool or a transaction.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+func insertVisit(ctx context.Context, q execer, userUUID, placeID string, at *Location, check VisitCheck) error {
+	var lat, lon, accuracy *float64
+	if at != nil {
+		lat, lon, accuracy = &at.Lat, &at.Lon, &at.AccuracyMeters
+	}
+	var reason, method *string
+	if check.Reason != "" {
+		reason = &check.Reason
+	}
+	if check.Method != "" {
+		method = &check.Method
+	}
+
+	_, err := q.Exec(ctx,
+		`INSERT INTO user_visit_history (user_uuid, place_id, latitude, longitude,
+			accuracy_meters, distance_meters, verified, verification_reason, verification_method)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		userUUID, placeID, lat, lon, accuracy, check.DistanceMeters, check.Verified, reason, method,
 	)
 	return err
 }
 
-func GetVisitHistory(ctx context.Context, userUUID string) ([]string, error) {
-	rows, err := db.Conn.Query(ctx,
-		`SELECT place_id FROM user_visit_history
-		 WHERE user_uuid=$1 ORDER BY visited_at DESC`,
-		userUUID,
-	)
+func publishVisit(ctx context.Context, userUUID, placeID string, check VisitCheck) {
+	events.Publish(ctx, events.Event{
+		Kind:     events.Visit,
+		UserUUID: userUUID,
+		PlaceID:  placeID,
+		Verified: check.Verified,
+		Method:   check.Method,
+	})
+}
+
+func GetVisitHistory(ctx context.Context, userUUID string, page pagination.Page, category string) ([]Visit, string, error) {
+	list, next, err := listPlaces(ctx, "user_visit_history", "visited_at", "t.verified", userUUID, page, category)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	defer rows.Close()
 
-	var visits []string
-	for rows.Next() {
-		var pid string
-		if err := rows.Scan(&pid); err == nil {
-			visits = append(visits, pid)
-		}
+	visits := make([]Visit, len(list))
+	for i, row := range list {
+		visits[i] = Visit{PlaceSummary: row.PlaceSummary, VisitedAt: row.At, Verified: row.Flag}
 	}
-	return visits, nil
+	return visits, next, nil
 }
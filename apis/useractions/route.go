@@ -0,0 +1,106 @@
+package useractions
+
+import "example.com/m/apis/findplaces"
+
+// Point is where a stop is, for ordering a route.
+type Point struct {
+	Lat, Lon float64
+}
+
+// OrderStops returns an order of points that keeps the total walk short:
+// nearest-neighbour paths improved with 2-opt, starting from the first point
+// when fixStart and otherwise from each point in turn, keeping the shortest.
+// Paths are open — they end at the last stop rather than returning to the
+// first.
+func OrderStops(points []Point, fixStart bool) []int {
+	n := len(points)
+	if n < 3 {
+		order := make([]int, n)
+		for i := range order {
+			order[i] = i
+		}
+		return order
+	}
+
+	dist := make([][]float64, n)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+		for j := range dist[i] {
+			dist[i][j] = findplaces.Haversine(points[i].Lat, points[i].Lon, points[j].Lat, points[j].Lon)
+		}
+	}
+
+	starts := n
+	if fixStart {
+		starts = 1
+	}
+	var best []int
+	bestLen := 0.0
+	for s := 0; s < starts; s++ {
+		order := nearestNeighbour(dist, s)
+		twoOpt(dist, order, fixStart)
+		if l := orderLength(dist, order); best == nil || l < bestLen {
+			best, bestLen = order, l
+		}
+	}
+	return best
+}
+
+func nearestNeighbour(dist [][]float64, start int) []int {
+	n := len(dist)
+	seen := make([]bool, n)
+	order := []int{start}
+	seen[start] = true
+	for len(order) < n {
+		from, next := order[len(order)-1], -1
+		for j := range n {
+			if !seen[j] && (next < 0 || dist[from][j] < dist[from][next]) {
+				next = j
+			}
+		}
+		seen[next] = true
+		order = append(order, next)
+	}
+	return order
+}
+
+// twoOpt reverses stretches of the path while that shortens it. On an open
+// path the ends have no outer edge, so only the inner joins are compared.
+func twoOpt(dist [][]float64, order []int, fixStart bool) {
+	n := len(order)
+	first := 0
+	if fixStart {
+		first = 1
+	}
+
+	for improved := true; improved; {
+		improved = false
+		for i := first; i < n-1; i++ {
+			for j := i + 1; j < n; j++ {
+				var before, after float64
+				if i > 0 {
+					before += dist[order[i-1]][order[i]]
+					after += dist[order[i-1]][order[j]]
+				}
+				if j < n-1 {
+					before += dist[order[j]][order[j+1]]
+					after += dist[order[i]][order[j+1]]
+				}
+				if after < before-1e-6 {
+					for a, b := i, j; a < b; a, b = a+1, b-1 {
+						order[a], order[b] = order[b], order[a]
+					}
+					improved = true
+				}
+			}
+		}
+	}
+}
+
+func orderLength(dist [][]float64, order []int) float64 {
+	var total float64
+	for i := 1; i < len(order); i++ {
+		total += dist[order[i-1]][order[i]]
+	}
+	return total
+}
@@ -0,0 +1,121 @@
+package useractions
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"example.com/m/apis/findplaces"
+)
+
+func distances(points []Point) [][]float64 {
+	dist := make([][]float64, len(points))
+	for i := range dist {
+		dist[i] = make([]float64, len(points))
+		for j := range dist[i] {
+			dist[i][j] = findplaces.Haversine(points[i].Lat, points[i].Lon, points[j].Lat, points[j].Lon)
+		}
+	}
+	return dist
+}
+
+func randomPoints(seed int64, n int) []Point {
+	rng := rand.New(rand.NewSource(seed))
+	points := make([]Point, n)
+	for i := range points {
+		points[i] = Point{Lat: 20 + rng.Float64()*0.1, Lon: 85 + rng.Float64()*0.1}
+	}
+	return points
+}
+
+var routeCases = []struct {
+	name   string
+	points []Point
+}{
+	{"empty", nil},
+	{"one", []Point{{20, 85}}},
+	{"two", []Point{{20, 85}, {20.01, 85}}},
+	{"line shuffled", []Point{{20, 85.03}, {20, 85}, {20, 85.04}, {20, 85.01}, {20, 85.02}}},
+	{"start in the middle", []Point{{20, 85.02}, {20, 85}, {20, 85.04}, {20, 85.01}, {20, 85.03}}},
+	{"zigzag", []Point{{20, 85}, {20.01, 85.01}, {20, 85.02}, {20.01, 85.03}, {20, 85.04}, {20.01, 85.05}}},
+	{"two clusters", []Point{{20, 85}, {20.1, 85.1}, {20.001, 85.001}, {20.101, 85.1}, {20, 85.002}, {20.1, 85.101}}},
+	{"duplicates", []Point{{20, 85}, {20, 85}, {20.01, 85.01}, {20.01, 85.01}}},
+	{"random 8", randomPoints(1, 8)},
+	{"random 15", randomPoints(2, 15)},
+	{"random 30", randomPoints(3, 30)},
+}
+
+func TestOrderStops(t *testing.T) {
+	for _, tt := range routeCases {
+		for _, fixStart := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/fixStart=%v", tt.name, fixStart), func(t *testing.T) {
+				order := OrderStops(tt.points, fixStart)
+
+				sorted := slices.Sorted(slices.Values(order))
+				for i, v := range sorted {
+					if v != i {
+						t.Fatalf("OrderStops(fixStart=%v) = %v, not a permutation of %d stops", fixStart, order, len(tt.points))
+					}
+				}
+				if len(sorted) != len(tt.points) {
+					t.Fatalf("OrderStops(fixStart=%v) returned %d stops, want %d", fixStart, len(order), len(tt.points))
+				}
+				if fixStart && len(order) > 0 && order[0] != 0 {
+					t.Errorf("OrderStops(fixStart=true) = %v, moved the first stop", order)
+				}
+
+				// The result is never worse than plain nearest-neighbour.
+				if len(tt.points) >= 3 {
+					dist := distances(tt.points)
+					nn := orderLength(dist, nearestNeighbour(dist, 0))
+					if got := orderLength(dist, order); got > nn+1e-6 {
+						t.Errorf("OrderStops(fixStart=%v) length %.1f, nearest-neighbour from 0 is %.1f", fixStart, got, nn)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestOrderStopsLine(t *testing.T) {
+	// Stops on a line are best walked end to end. A parallel isn't a great
+	// circle, so the legs add up to a little more than the direct distance.
+	points := routeCases[3].points
+	dist := distances(points)
+	want := dist[1][2] // the two ends
+
+	order := OrderStops(points, false)
+	if got := orderLength(dist, order); got > want+1 {
+		t.Errorf("OrderStops = %v, length %.1f, want %.1f", order, got, want)
+	}
+}
+
+func TestTwoOpt(t *testing.T) {
+	for _, tt := range routeCases {
+		if len(tt.points) < 3 {
+			continue
+		}
+		dist := distances(tt.points)
+		for _, fixStart := range []bool{false, true} {
+			for start := range tt.points {
+				t.Run(fmt.Sprintf("%s/fixStart=%v/start=%d", tt.name, fixStart, start), func(t *testing.T) {
+					order := nearestNeighbour(dist, start)
+					before := orderLength(dist, order)
+
+					twoOpt(dist, order, fixStart)
+
+					if after := orderLength(dist, order); after > before+1e-6 {
+						t.Errorf("twoOpt(fixStart=%v) from %d lengthened the path: %.1f -> %.1f", fixStart, start, before, after)
+					}
+					if fixStart && order[0] != start {
+						t.Errorf("twoOpt(fixStart=true) moved the start from %d to %d", start, order[0])
+					}
+					if len(order) != len(tt.points) || len(slices.Compact(slices.Sorted(slices.Values(order)))) != len(tt.points) {
+						t.Errorf("twoOpt(fixStart=%v) = %v, not a permutation", fixStart, order)
+					}
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package useractions
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SharedRoutes serves collections by their public share link; it needs no
+// sign-in.
+func SharedRoutes() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/collections/{token}", SharedCollectionHandler)
+	return r
+}
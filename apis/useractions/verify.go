@@ -0,0 +1,115 @@
+package useractions
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"example.com/m/apis/findplaces"
+	"example.com/m/db"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// geofenceRadius is how close (in meters) the user must be to a place,
+	// give or take their reported accuracy, for a visit to count.
+	geofenceRadius = 250.0
+	// maxAccuracy rejects fixes too vague to tell which site the user is at.
+	maxAccuracy = 150.0
+	// maxTravelSpeed (m/s, about 900 km/h) is faster than anyone moves
+	// between visits without a plane.
+	maxTravelSpeed = 250.0
+)
+
+// Reasons a visit was left unverified.
+const (
+	ReasonNoLocation       = "no_location"
+	ReasonLowAccuracy      = "low_accuracy"
+	ReasonTooFar           = "too_far"
+	ReasonSpoofedLocation  = "spoofed_location"
+	ReasonImpossibleTravel = "impossible_travel"
+	ReasonUnknownPlace     = "unknown_place"
+)
+
+// How a visit was verified.
+const (
+	MethodGPS = "gps"
+	MethodQR  = "qr"
+)
+
+// Location is where the user's device says they are.
+type Location struct {
+	Lat            float64
+	Lon            float64
+	AccuracyMeters float64
+}
+
+// VisitCheck is the outcome of verifying a visit.
+type VisitCheck struct {
+	Verified       bool     `json:"verified"`
+	Reason         string   `json:"reason,omitempty" enums:"no_location,low_accuracy,too_far,spoofed_location,impossible_travel,unknown_place"`
+	DistanceMeters *float64 `json:"distance_meters,omitempty"`
+	Method         string   `json:"method,omitempty" enums:"gps,qr"`
+}
+
+// lastLocatedVisit returns the user's most recent visit that carried a
+// location, if any.
+func lastLocatedVisit(ctx context.Context, userUUID string) (Location, time.Time, bool, error) {
+	var loc Location
+	var at time.Time
+	err := db.Conn.QueryRow(ctx,
+		`SELECT latitude, longitude, COALESCE(accuracy_meters, 0), visited_at
+		 FROM user_visit_history
+		 WHERE user_uuid=$1 AND latitude IS NOT NULL AND longitude IS NOT NULL
+		 ORDER BY visited_at DESC LIMIT 1`,
+		userUUID,
+	).Scan(&loc.Lat, &loc.Lon, &loc.AccuracyMeters, &at)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Location{}, time.Time{}, false, nil
+	}
+	return loc, at, err == nil, err
+}
+
+// VerifyVisit checks that the user was at place when they logged a visit:
+// inside the geofence, with a usable fix, not sitting exactly on the
+// catalog coordinates (a copied location, not a GPS reading), and not
+// having had to travel impossibly fast since their last located visit.
+func VerifyVisit(ctx context.Context, userUUID string, place findplaces.Place, at *Location, now time.Time) (VisitCheck, error) {
+	if at == nil {
+		return VisitCheck{Reason: ReasonNoLocation}, nil
+	}
+
+	distance := findplaces.Haversine(at.Lat, at.Lon, place.Lat, place.Lon)
+	check := VisitCheck{DistanceMeters: &distance, Method: MethodGPS}
+
+	switch {
+	case at.AccuracyMeters > maxAccuracy:
+		check.Reason = ReasonLowAccuracy
+		return check, nil
+	case at.Lat == place.Lat && at.Lon == place.Lon:
+		check.Reason = ReasonSpoofedLocation
+		return check, nil
+	case distance-at.AccuracyMeters > geofenceRadius:
+		check.Reason = ReasonTooFar
+		return check, nil
+	}
+
+	prev, prevAt, ok, err := lastLocatedVisit(ctx, userUUID)
+	if err != nil {
+		return VisitCheck{}, err
+	}
+	if ok {
+		// Give the benefit of both fixes' uncertainty before judging speed.
+		moved := findplaces.Haversine(prev.Lat, prev.Lon, at.Lat, at.Lon) -
+			prev.AccuracyMeters - at.AccuracyMeters
+		elapsed := math.Max(now.Sub(prevAt).Seconds(), 1)
+		if moved > 0 && moved/elapsed > maxTravelSpeed {
+			check.Reason = ReasonImpossibleTravel
+			return check, nil
+		}
+	}
+
+	check.Verified = true
+	return check, nil
+}
@@ -0,0 +1,173 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"example.com/m/db"
+	"example.com/m/i18n"
+	"example.com/m/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type FriendRequest struct {
+	FriendUUID string `json:"friend_uuid"`
+}
+
+type Friend struct {
+	UUID      string  `json:"uuid"`
+	Name      string  `json:"name"`
+	AvatarURL *string `json:"avatar_url,omitempty"`
+}
+
+type FriendsResponse struct {
+	Friends []Friend `json:"friends"`
+}
+
+func listFriends(ctx context.Context, userUUID string) ([]Friend, error) {
+	rows, err := db.Conn.Query(ctx,
+		`SELECT u.uuid::text, COALESCE(u.name, ''), u.avatar_url
+		 FROM user_friends f JOIN users u ON u.uuid = f.friend_uuid
+		 WHERE f.user_uuid=$1
+		 ORDER BY f.created_at DESC`,
+		userUUID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	friends := []Friend{}
+	for rows.Next() {
+		var f Friend
+		if err := rows.Scan(&f.UUID, &f.Name, &f.AvatarURL); err == nil {
+			friends = append(friends, f)
+		}
+	}
+	return friends, rows.Err()
+}
+
+// GetFriendsHandler lists the user's friends
+// @Summary List Friends
+// @Description Users the caller has added as friends, who make up their friends leaderboard
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} FriendsResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/friends [get]
+func GetFriendsHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	friends, err := listFriends(r.Context(), userUUID)
+	if err != nil {
+		log.Printf("Listing friends failed: %v", err)
+		i18n.Error(w, r, i18n.FriendsFailed, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(FriendsResponse{Friends: friends})
+}
+
+// AddFriendHandler adds a friend
+// @Summary Add Friend
+// @Description Add another user as a friend. Adding someone twice is a no-op.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body FriendRequest true "Friend"
+// @Success 200 {object} map[string]string "status: added"
+// @Failure 400 {object} map[string]string "Invalid friend_uuid"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "User not found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/friends [post]
+func AddFriendHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	var req FriendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		i18n.Error(w, r, i18n.InvalidFriend, http.StatusBadRequest)
+		return
+	}
+	friend, err := uuid.Parse(req.FriendUUID)
+	if err != nil || friend.String() == userUUID {
+		i18n.Error(w, r, i18n.InvalidFriend, http.StatusBadRequest)
+		return
+	}
+
+	tag, err := db.Conn.Exec(r.Context(),
+		`INSERT INTO user_friends (user_uuid, friend_uuid)
+		 SELECT $1, uuid FROM users WHERE uuid=$2
+		 ON CONFLICT (user_uuid, friend_uuid) DO NOTHING`,
+		userUUID, friend.String(),
+	)
+	if err != nil {
+		log.Printf("Adding friend failed: %v", err)
+		i18n.Error(w, r, i18n.FriendsFailed, http.StatusInternalServerError)
+		return
+	}
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		db.Conn.QueryRow(r.Context(), `SELECT EXISTS (SELECT 1 FROM users WHERE uuid=$1)`, friend.String()).Scan(&exists)
+		if !exists {
+			i18n.Error(w, r, i18n.UserNotFound, http.StatusNotFound)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "added"})
+}
+
+// RemoveFriendHandler removes a friend
+// @Summary Remove Friend
+// @Description Remove a user from the caller's friends
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Param friend_uuid path string true "Friend's UUID"
+// @Success 200 {object} map[string]string "status: removed"
+// @Failure 400 {object} map[string]string "Invalid friend_uuid"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /api/user/friends/{friend_uuid} [delete]
+func RemoveFriendHandler(w http.ResponseWriter, r *http.Request) {
+	userUUID, err := utils.GetUserUUIDFromCtx(r.Context())
+	if err != nil {
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	friend, err := uuid.Parse(chi.URLParam(r, "friend_uuid"))
+	if err != nil {
+		i18n.Error(w, r, i18n.InvalidFriend, http.StatusBadRequest)
+		return
+	}
+
+	if _, err := db.Conn.Exec(r.Context(),
+		`DELETE FROM user_friends WHERE user_uuid=$1 AND friend_uuid=$2`,
+		userUUID, friend.String(),
+	); err != nil {
+		log.Printf("Removing friend failed: %v", err)
+		i18n.Error(w, r, i18n.FriendsFailed, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "removed"})
+}
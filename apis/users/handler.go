@@ -1,6 +1,7 @@
 package users
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,7 +9,9 @@ import (
 	"net/http"
 	"time"
 
+	"example.com/m/apis/xp"
 	"example.com/m/db"
+	"example.com/m/i18n"
 	"example.com/m/middleware"
 	"example.com/m/utils"
 	"github.com/google/uuid"
@@ -44,7 +47,7 @@ func GetProfileHandler(w http.ResponseWriter, r *http.Request) {
 
 	uuidStr, err := utils.GetUserUUIDFromCtx(ctx)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
 		return
 	}
 
@@ -70,11 +73,11 @@ func GetProfileHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			log.Printf("❌ No user found for UUID: %s", uuidStr)
-			http.Error(w, "User not found", http.StatusNotFound)
+			i18n.Error(w, r, i18n.UserNotFound, http.StatusNotFound)
 			return
 		}
 		log.Printf("🔥 DB Error fetching user profile: %+v", err)
-		http.Error(w, "Database error", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.DatabaseError, http.StatusInternalServerError)
 		return
 	}
 
@@ -117,20 +120,20 @@ func UpdateProfileHandler(w http.ResponseWriter, r *http.Request) {
 
 	uuidStr, ok := uuidVal.(string)
 	if !ok || uuidStr == "" {
-		http.Error(w, "User UUID missing in request context", http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	var payload UpdateProfileRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		i18n.Error(w, r, i18n.InvalidJSON, http.StatusBadRequest)
 		return
 	}
 
 	prefsJSON, err := json.Marshal(payload.Preferences)
 	if err != nil {
-		http.Error(w, "Failed to serialize preferences", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.UpdateProfile, http.StatusInternalServerError)
 		return
 	}
 
@@ -146,7 +149,7 @@ func UpdateProfileHandler(w http.ResponseWriter, r *http.Request) {
 		uuidStr,
 	)
 	if err != nil {
-		http.Error(w, "Failed to update profile", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.UpdateProfile, http.StatusInternalServerError)
 		return
 	}
 
@@ -171,33 +174,33 @@ func UploadAvatarHandler(w http.ResponseWriter, r *http.Request) {
 
 	uuidStr, err := utils.GetUserUUIDFromCtx(ctx)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
 		return
 	}
 
 	r.Body = http.MaxBytesReader(w, r.Body, 5<<20) // 5MB
 	if err := r.ParseMultipartForm(5 << 20); err != nil {
-		http.Error(w, "File too big", http.StatusBadRequest)
+		i18n.Error(w, r, i18n.FileTooBig, http.StatusBadRequest)
 		return
 	}
 
 	file, _, err := r.FormFile("avatar")
 	if err != nil {
-		http.Error(w, "Avatar file required", http.StatusBadRequest)
+		i18n.Error(w, r, i18n.AvatarRequired, http.StatusBadRequest)
 		return
 	}
 	defer file.Close()
 
 	fileBytes, err := io.ReadAll(file)
 	if err != nil {
-		http.Error(w, "Failed to read file", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.ReadFileFailed, http.StatusInternalServerError)
 		return
 	}
 
 	fileName := fmt.Sprintf("%s_%s", uuidStr, uuid.New().String())
 	url, err := middleware.UploadFile(ctx, fileBytes, fileName)
 	if err != nil || url == "" {
-		http.Error(w, "Failed to upload avatar", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.UploadAvatarFailed, http.StatusInternalServerError)
 		return
 	}
 
@@ -206,7 +209,7 @@ func UploadAvatarHandler(w http.ResponseWriter, r *http.Request) {
 		url, uuidStr,
 	)
 	if err != nil {
-		http.Error(w, "Failed to save avatar URL", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.UploadAvatarFailed, http.StatusInternalServerError)
 		return
 	}
 
@@ -215,6 +218,7 @@ func UploadAvatarHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 type UserStats struct {
+	xp.Summary
 	Badges     int `json:"badges"`
 	Challenges struct {
 		Total    int            `json:"total"`
@@ -223,31 +227,64 @@ type UserStats struct {
 	} `json:"challenges"`
 }
 
+// userLocation picks the timezone streaks are counted in: the tz parameter,
+// else preferences.timezone, else UTC. Only a bad tz parameter is an error;
+// a bad saved preference falls back to UTC.
+func userLocation(ctx context.Context, uuidStr, param string) (*time.Location, error) {
+	if param != "" {
+		return time.LoadLocation(param)
+	}
+
+	var name string
+	db.Conn.QueryRow(ctx,
+		`SELECT COALESCE(preferences->>'timezone', '') FROM users WHERE uuid=$1`, uuidStr,
+	).Scan(&name)
+	if loc, err := time.LoadLocation(name); err == nil && name != "" {
+		return loc, nil
+	}
+	return time.UTC, nil
+}
+
 // GetStatsHandler returns user statistics
 // @Summary Get User Stats
-// @Description Retrieve statistics for the authenticated user
+// @Description Retrieve statistics for the authenticated user: XP, level, verified-visit streaks, badges and challenges. Streak days and weeks follow the tz parameter, else the timezone in the user's preferences, else UTC.
 // @Tags users
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param tz query string false "IANA timezone for streaks, e.g. Asia/Kolkata"
 // @Success 200 {object} UserStats
+// @Failure 400 {object} map[string]string "Invalid timezone"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /api/user/stats [get]
 func GetStatsHandler(w http.ResponseWriter, r *http.Request) {
 	uuidStr, err := utils.GetUserUUIDFromCtx(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.Unauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	loc, err := userLocation(r.Context(), uuidStr, r.URL.Query().Get("tz"))
+	if err != nil {
+		i18n.Error(w, r, i18n.InvalidTimezone, http.StatusBadRequest)
 		return
 	}
 
 	var stats UserStats
 
+	stats.Summary, err = xp.UserSummary(r.Context(), uuidStr, loc)
+	if err != nil {
+		log.Printf("Loading XP failed: %v", err)
+		i18n.Error(w, r, i18n.GetStatsFailed, http.StatusInternalServerError)
+		return
+	}
+
 	// badges
 	err = db.Conn.QueryRow(r.Context(),
 		`SELECT COUNT(*) FROM user_badges WHERE user_uuid=$1`, uuidStr).Scan(&stats.Badges)
 	if err != nil {
-		http.Error(w, "Failed to get badges", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.GetBadgesFailed, http.StatusInternalServerError)
 		return
 	}
 
@@ -256,7 +293,7 @@ func GetStatsHandler(w http.ResponseWriter, r *http.Request) {
 	rows, err := db.Conn.Query(r.Context(),
 		`SELECT status, COUNT(*) FROM user_challenges WHERE user_uuid=$1 GROUP BY status`, uuidStr)
 	if err != nil {
-		http.Error(w, "Failed to get challenges", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.GetChallenges, http.StatusInternalServerError)
 		return
 	}
 	defer rows.Close()
@@ -3,6 +3,11 @@ package users
 import (
 	"net/http"
 
+	"example.com/m/apis/badges"
+	"example.com/m/apis/challenges"
+	"example.com/m/apis/checkin"
+	"example.com/m/apis/leaderboards"
+	"example.com/m/apis/reviews"
 	"example.com/m/apis/useractions"
 	"example.com/m/middleware"
 	"github.com/go-chi/chi/v5"
@@ -23,6 +28,44 @@ func Routes() http.Handler {
 		protected.Delete("/save-place/{place_id}", useractions.RemoveSavedPlaceHandler)
 		protected.Post("/visit", useractions.LogVisitHandler)
 		protected.Get("/visit-history", useractions.GetVisitHistoryHandler)
+		protected.Post("/checkin/qr", checkin.QRCheckinHandler)
+		protected.Get("/badges", badges.ListHandler)
+		protected.Get("/challenges", challenges.ListHandler)
+		protected.Get("/challenges/{id}", challenges.GetHandler)
+		protected.Post("/challenges/{id}/enroll", challenges.EnrollHandler)
+		protected.Get("/leaderboard", leaderboards.LeaderboardHandler)
+		protected.Get("/friends", GetFriendsHandler)
+		protected.Post("/friends", AddFriendHandler)
+		protected.Delete("/friends/{friend_uuid}", RemoveFriendHandler)
+		protected.Get("/collections", useractions.ListCollectionsHandler)
+		protected.Post("/collections", useractions.CreateCollectionHandler)
+		protected.Get("/collections/{id}", useractions.GetCollectionHandler)
+		protected.Patch("/collections/{id}", useractions.UpdateCollectionHandler)
+		protected.Delete("/collections/{id}", useractions.DeleteCollectionHandler)
+		protected.Post("/collections/{id}/places", useractions.AddCollectionPlaceHandler)
+		protected.Patch("/collections/{id}/places/{place_id}", useractions.UpdateCollectionPlaceHandler)
+		protected.Delete("/collections/{id}/places/{place_id}", useractions.RemoveCollectionPlaceHandler)
+		protected.Put("/collections/{id}/order", useractions.ReorderCollectionHandler)
+		protected.Post("/collections/{id}/share-link", useractions.CreateShareLinkHandler)
+		protected.Delete("/collections/{id}/share-link", useractions.RevokeShareLinkHandler)
+		protected.Post("/collections/{id}/shares", useractions.ShareCollectionHandler)
+		protected.Delete("/collections/{id}/shares/{user_uuid}", useractions.UnshareCollectionHandler)
+		protected.Get("/itineraries", useractions.ListItinerariesHandler)
+		protected.Post("/itineraries", useractions.CreateItineraryHandler)
+		protected.Get("/itineraries/{id}", useractions.GetItineraryHandler)
+		protected.Patch("/itineraries/{id}", useractions.UpdateItineraryHandler)
+		protected.Delete("/itineraries/{id}", useractions.DeleteItineraryHandler)
+		protected.Post("/itineraries/{id}/stops", useractions.AddStopHandler)
+		protected.Patch("/itineraries/{id}/stops/{stop_id}", useractions.UpdateStopHandler)
+		protected.Delete("/itineraries/{id}/stops/{stop_id}", useractions.RemoveStopHandler)
+		protected.Put("/itineraries/{id}/days/{day}/order", useractions.ReorderDayHandler)
+		protected.Post("/itineraries/{id}/days/{day}/optimize", useractions.OptimizeDayHandler)
+		protected.Get("/places/{place_id}/reviews", reviews.ListHandler)
+		protected.Get("/places/{place_id}/review", reviews.GetHandler)
+		protected.Put("/places/{place_id}/review", reviews.SaveHandler)
+		protected.Delete("/places/{place_id}/review", reviews.DeleteHandler)
+		protected.Post("/reviews/{id}/helpful", reviews.HelpfulHandler)
+		protected.Delete("/reviews/{id}/helpful", reviews.UnhelpfulHandler)
 	})
 
 	return r
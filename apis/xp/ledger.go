@@ -0,0 +1,107 @@
+package xp
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"example.com/m/db"
+	"example.com/m/events"
+)
+
+// What each action is worth.
+const (
+	pointsVisit         = 5
+	pointsVerifiedVisit = 15 // on top of pointsVisit
+	pointsCheckin       = 10 // on top of a verified visit
+	pointsBadge         = 50
+	pointsChallenge     = 100
+	pointsReview        = 15
+
+	// maxPaidVisitsPerDay caps how many visits earn XP in a (UTC) day.
+	maxPaidVisitsPerDay = 20
+)
+
+// Ledger reasons.
+const (
+	ReasonVisit         = "visit"
+	ReasonVerifiedVisit = "verified_visit"
+	ReasonCheckin       = "checkin"
+	ReasonBadge         = "badge"
+	ReasonChallenge     = "challenge"
+	ReasonReview        = "review"
+)
+
+// Init grants XP as users act.
+func Init() {
+	events.Subscribe(events.Visit, onVisit)
+	events.Subscribe(events.BadgeAwarded, onAward(ReasonBadge, pointsBadge))
+	events.Subscribe(events.ChallengeCompleted, onAward(ReasonChallenge, pointsChallenge))
+	events.Subscribe(events.Review, onReview)
+}
+
+// Grant adds points to the user's ledger once per (reason, ref).
+func Grant(ctx context.Context, userUUID, reason, ref string, points int) error {
+	tag, err := db.Conn.Exec(ctx,
+		`INSERT INTO xp_ledger (user_uuid, reason, ref, points) VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (user_uuid, reason, ref) DO NOTHING`,
+		userUUID, reason, ref, points,
+	)
+	if err != nil {
+		log.Printf("Granting %s XP failed: %v", reason, err)
+		return err
+	}
+
+	if tag.RowsAffected() == 1 {
+		events.Publish(ctx, events.Event{Kind: events.XPGranted, UserUUID: userUUID, Ref: reason, Points: points})
+	}
+	return nil
+}
+
+// onVisit pays for verified visits only: an unverified visit can name any
+// place_id at all. Each place pays at most once per (UTC) day, and at most
+// maxPaidVisitsPerDay places pay per day, so logging visits over and over
+// earns nothing.
+func onVisit(ctx context.Context, e events.Event) {
+	if !e.Verified {
+		return
+	}
+
+	day := e.At.UTC().Truncate(24 * time.Hour)
+	var paid int
+	err := db.Conn.QueryRow(ctx,
+		`SELECT COUNT(*) FROM xp_ledger
+		 WHERE user_uuid=$1 AND reason=$2 AND created_at >= $3 AND created_at < $4`,
+		e.UserUUID, ReasonVisit, day, day.Add(24*time.Hour),
+	).Scan(&paid)
+	if err != nil {
+		log.Printf("Counting paid visits failed: %v", err)
+		return
+	}
+	if paid >= maxPaidVisitsPerDay {
+		return
+	}
+
+	ref := e.PlaceID + ":" + day.Format("2006-01-02")
+	Grant(ctx, e.UserUUID, ReasonVisit, ref, pointsVisit)
+	Grant(ctx, e.UserUUID, ReasonVerifiedVisit, ref, pointsVerifiedVisit)
+	if e.Method == "qr" {
+		Grant(ctx, e.UserUUID, ReasonCheckin, ref, pointsCheckin)
+	}
+}
+
+func onAward(reason string, points int) events.Handler {
+	return func(ctx context.Context, e events.Event) {
+		Grant(ctx, e.UserUUID, reason, e.Ref, points)
+	}
+}
+
+// onReview pays once per place reviewed, however often it's edited, and only
+// for reviews backed by a verified visit: like onVisit, an unverified visit
+// proves nothing.
+func onReview(ctx context.Context, e events.Event) {
+	if !e.Verified {
+		return
+	}
+	Grant(ctx, e.UserUUID, ReasonReview, e.PlaceID, pointsReview)
+}
@@ -0,0 +1,152 @@
+package xp
+
+import (
+	"context"
+	"time"
+
+	"example.com/m/db"
+)
+
+// streakLookback bounds how many distinct visit days are read to work out
+// streaks.
+const streakLookback = 400
+
+type Streaks struct {
+	// Daily counts consecutive days with a verified visit, ending today (or
+	// yesterday, if the user hasn't been out yet today).
+	Daily int `json:"daily"`
+	// Weekly counts consecutive Monday-to-Sunday weeks with a verified visit,
+	// ending this week or last.
+	Weekly   int    `json:"weekly"`
+	Timezone string `json:"timezone"`
+}
+
+type Summary struct {
+	XP          int     `json:"xp"`
+	Level       int     `json:"level"`
+	LevelXP     int     `json:"level_xp"`
+	NextLevelXP int     `json:"next_level_xp"`
+	Streaks     Streaks `json:"streaks"`
+}
+
+// levelThreshold is the XP needed to reach level n: 0, 100, 300, 600, ...
+func levelThreshold(n int) int {
+	return 50 * n * (n - 1)
+}
+
+// Level returns the level for an XP total.
+func Level(xp int) int {
+	n := 1
+	for levelThreshold(n+1) <= xp {
+		n++
+	}
+	return n
+}
+
+func Total(ctx context.Context, userUUID string) (int, error) {
+	var total int
+	err := db.Conn.QueryRow(ctx,
+		`SELECT COALESCE(SUM(points), 0) FROM xp_ledger WHERE user_uuid=$1`, userUUID,
+	).Scan(&total)
+	return total, err
+}
+
+// visitDays returns the distinct local dates (in loc) the user made a
+// verified visit, most recent first. Unverified visits don't count, as they
+// don't for XP.
+func visitDays(ctx context.Context, userUUID string, loc *time.Location) ([]time.Time, error) {
+	rows, err := db.Conn.Query(ctx,
+		`SELECT DISTINCT (visited_at AT TIME ZONE $2)::date AS day
+		 FROM user_visit_history WHERE user_uuid=$1 AND verified
+		 ORDER BY day DESC LIMIT $3`,
+		userUUID, loc.String(), streakLookback,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err == nil {
+			days = append(days, day)
+		}
+	}
+	return days, rows.Err()
+}
+
+// civil reduces t to its calendar date as a UTC midnight, so dates can be
+// compared and stepped without DST getting in the way.
+func civil(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7 // Monday is 0
+	return day.AddDate(0, 0, -offset)
+}
+
+// streak counts consecutive periods in periods (distinct, most recent
+// first, step apart) ending at current or the period before it.
+func streak(periods []time.Time, current time.Time, step func(time.Time) time.Time) int {
+	if len(periods) == 0 {
+		return 0
+	}
+	expect := current
+	if !periods[0].Equal(expect) {
+		expect = step(current)
+	}
+
+	n := 0
+	for _, p := range periods {
+		if !p.Equal(expect) {
+			break
+		}
+		n++
+		expect = step(expect)
+	}
+	return n
+}
+
+func streaks(days []time.Time, now time.Time) (daily, weekly int) {
+	today := civil(now)
+	prevDay := func(t time.Time) time.Time { return t.AddDate(0, 0, -1) }
+	prevWeek := func(t time.Time) time.Time { return t.AddDate(0, 0, -7) }
+
+	dates := make([]time.Time, len(days))
+	var weeks []time.Time
+	for i, d := range days {
+		dates[i] = civil(d)
+		w := weekStart(dates[i])
+		if len(weeks) == 0 || !weeks[len(weeks)-1].Equal(w) {
+			weeks = append(weeks, w)
+		}
+	}
+
+	return streak(dates, today, prevDay), streak(weeks, weekStart(today), prevWeek)
+}
+
+// UserSummary returns the user's XP, level and streaks, with day and week
+// boundaries taken in loc.
+func UserSummary(ctx context.Context, userUUID string, loc *time.Location) (Summary, error) {
+	total, err := Total(ctx, userUUID)
+	if err != nil {
+		return Summary{}, err
+	}
+	days, err := visitDays(ctx, userUUID, loc)
+	if err != nil {
+		return Summary{}, err
+	}
+
+	level := Level(total)
+	s := Summary{
+		XP:          total,
+		Level:       level,
+		LevelXP:     levelThreshold(level),
+		NextLevelXP: levelThreshold(level + 1),
+		Streaks:     Streaks{Timezone: loc.String()},
+	}
+	s.Streaks.Daily, s.Streaks.Weekly = streaks(days, time.Now().In(loc))
+	return s, nil
+}
@@ -0,0 +1,99 @@
+package xp
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestStreaks(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Visit days come back from the database as dates, i.e. UTC midnights.
+	dates := func(ds ...string) []time.Time {
+		out := make([]time.Time, len(ds))
+		for i, d := range ds {
+			out[i], _ = time.Parse(time.DateOnly, d)
+		}
+		return out
+	}
+	at := func(loc *time.Location, s string) time.Time {
+		t, _ := time.ParseInLocation("2006-01-02 15:04", s, loc)
+		return t
+	}
+
+	tests := []struct {
+		name          string
+		days          []time.Time
+		now           time.Time
+		daily, weekly int
+	}{
+		{"no visits", nil, at(newYork, "2026-03-10 12:00"), 0, 0},
+		{"today only", dates("2026-03-10"), at(newYork, "2026-03-10 12:00"), 1, 1},
+		{"ending yesterday", dates("2026-03-09", "2026-03-08"), at(newYork, "2026-03-10 12:00"), 2, 2},
+		{"broken two days ago", dates("2026-03-08", "2026-03-07"), at(newYork, "2026-03-10 12:00"), 0, 1},
+		{"gap", dates("2026-03-10", "2026-03-09", "2026-03-07"), at(newYork, "2026-03-10 12:00"), 2, 2},
+
+		// Clocks go forward on 2026-03-08 in New York: that day is 23 hours.
+		{"across spring forward", dates("2026-03-09", "2026-03-08", "2026-03-07"), at(newYork, "2026-03-09 00:30"), 3, 2},
+		{"just before spring forward", dates("2026-03-08", "2026-03-07"), at(newYork, "2026-03-08 01:59"), 2, 1},
+		// Clocks go back on 2026-11-01: that day is 25 hours.
+		{"across fall back", dates("2026-11-02", "2026-11-01", "2026-10-31", "2026-10-26"), at(newYork, "2026-11-02 00:30"), 3, 2},
+		{"late on fall back day", dates("2026-11-01", "2026-10-31"), at(newYork, "2026-11-01 23:59"), 2, 1},
+		// Late evening in New York is already tomorrow in UTC.
+		{"local date, not UTC", dates("2026-03-08"), at(newYork, "2026-03-08 23:30"), 1, 1},
+		// Early morning in Kolkata is still yesterday in UTC.
+		{"ahead of UTC", dates("2026-03-10", "2026-03-09"), at(kolkata, "2026-03-10 01:00"), 2, 1},
+
+		// Weeks run Monday to Sunday; 2026-03-09 is a Monday.
+		{"sunday then monday", dates("2026-03-09", "2026-03-08"), at(newYork, "2026-03-09 18:00"), 2, 2},
+		{"last week only", dates("2026-03-08", "2026-03-02"), at(newYork, "2026-03-09 18:00"), 1, 1},
+		{"one visit a week", dates("2026-03-11", "2026-03-04", "2026-02-23"), at(newYork, "2026-03-12 09:00"), 1, 3},
+		{"skipped a week", dates("2026-03-11", "2026-02-25"), at(newYork, "2026-03-12 09:00"), 1, 1},
+		{"nothing this week or last", dates("2026-02-25"), at(newYork, "2026-03-12 09:00"), 0, 0},
+		// The week of 2026-12-28 runs into the new year.
+		{"across new year", dates("2027-01-01", "2026-12-31", "2026-12-28", "2026-12-21"), at(newYork, "2027-01-02 10:00"), 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			daily, weekly := streaks(tt.days, tt.now)
+			if daily != tt.daily || weekly != tt.weekly {
+				t.Errorf("streaks(%v, %v) = %d, %d; want %d, %d", tt.days, tt.now, daily, weekly, tt.daily, tt.weekly)
+			}
+		})
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct{ day, want string }{
+		{"2026-03-09", "2026-03-09"}, // Monday
+		{"2026-03-15", "2026-03-09"}, // Sunday
+		{"2026-03-11", "2026-03-09"},
+		{"2026-11-01", "2026-10-26"},
+		{"2027-01-01", "2026-12-28"},
+	}
+	for _, tt := range tests {
+		day, _ := time.Parse(time.DateOnly, tt.day)
+		if got := weekStart(day).Format(time.DateOnly); got != tt.want {
+			t.Errorf("weekStart(%s) = %s, want %s", tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tests := []struct{ xp, want int }{
+		{0, 1}, {99, 1}, {100, 2}, {299, 2}, {300, 3}, {600, 4}, {4950, 10},
+	}
+	for _, tt := range tests {
+		if got := Level(tt.xp); got != tt.want {
+			t.Errorf("Level(%d) = %d, want %d", tt.xp, got, tt.want)
+		}
+	}
+}
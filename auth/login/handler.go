@@ -8,6 +8,7 @@ import (
 
 	"example.com/m/auth"
 	"example.com/m/db"
+	"example.com/m/i18n"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -28,12 +29,6 @@ type LoginResponse struct {
 	AccessExpires string `json:"accessExpires"`
 }
 
-func jsonError(w http.ResponseWriter, message string, code int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(JSONResponse{"error": message})
-}
-
 func jsonSuccess(w http.ResponseWriter, payload JSONResponse) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -54,13 +49,13 @@ func jsonSuccess(w http.ResponseWriter, payload JSONResponse) {
 // @Router /login [post]
 func Handler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		jsonError(w, "Method not allowed", http.StatusMethodNotAllowed)
+		i18n.JSONError(w, r, i18n.MethodNotAllowed, http.StatusMethodNotAllowed)
 		return
 	}
 
 	var req LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		jsonError(w, "Invalid JSON", http.StatusBadRequest)
+		i18n.JSONError(w, r, i18n.InvalidJSON, http.StatusBadRequest)
 		return
 	}
 
@@ -73,12 +68,12 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 	).Scan(&UUID, &storedHash)
 
 	if err != nil {
-		jsonError(w, "Invalid email or password", http.StatusUnauthorized)
+		i18n.JSONError(w, r, i18n.InvalidCredentials, http.StatusUnauthorized)
 		return
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(req.Password)); err != nil {
-		jsonError(w, "Invalid email or password", http.StatusUnauthorized)
+		i18n.JSONError(w, r, i18n.InvalidCredentials, http.StatusUnauthorized)
 		return
 	}
 
@@ -91,7 +86,7 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 	_, accessToken, refreshToken, genAt, accessExp, err := auth.GenerateJWT(UUID, req.Email, 0, 0)
 	if err != nil {
 		log.Printf("JWT generation error: %+v\n", err)
-		jsonError(w, "Could not generate tokens", http.StatusInternalServerError)
+		i18n.JSONError(w, r, i18n.TokenFailed, http.StatusInternalServerError)
 		return
 	}
 
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"example.com/m/i18n"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
@@ -33,13 +34,13 @@ type refreshResponse struct {
 // @Router /refresh [post]
 func RefreshHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		i18n.Error(w, r, i18n.MethodNotAllowed, http.StatusMethodNotAllowed)
 		return
 	}
 
 	var req refreshRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		i18n.Error(w, r, i18n.InvalidJSON, http.StatusBadRequest)
 		return
 	}
 
@@ -48,36 +49,36 @@ func RefreshHandler(w http.ResponseWriter, r *http.Request) {
 		return jwtSecret, nil
 	})
 	if err != nil || !token.Valid {
-		http.Error(w, "invalid refresh token", http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.InvalidRefresh, http.StatusUnauthorized)
 		return
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		http.Error(w, "invalid token claims", http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.InvalidRefresh, http.StatusUnauthorized)
 		return
 	}
 
 	if typ, _ := claims["type"].(string); typ != "refresh" {
-		http.Error(w, "invalid token type", http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.InvalidRefresh, http.StatusUnauthorized)
 		return
 	}
 
 	email, ok := claims["email"].(string)
 	if !ok {
-		http.Error(w, "invalid token data: email", http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.InvalidRefresh, http.StatusUnauthorized)
 		return
 	}
 
 	userUUIDStr, ok := claims["user_uuid"].(string)
 	if !ok {
-		http.Error(w, "invalid token data: uuid", http.StatusUnauthorized)
+		i18n.Error(w, r, i18n.InvalidRefresh, http.StatusUnauthorized)
 		return
 	}
 
 	userUUID, err := uuid.Parse(userUUIDStr)
 	if err != nil {
-		http.Error(w, "invalid uuid format", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.InvalidRefresh, http.StatusUnauthorized)
 		return
 	}
 
@@ -85,7 +86,7 @@ func RefreshHandler(w http.ResponseWriter, r *http.Request) {
 	_, accessToken, _, genAt, accessExp, err :=
 		GenerateJWT(userUUID, email, 0, 0)
 	if err != nil {
-		http.Error(w, "failed to generate access token", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.TokenFailed, http.StatusInternalServerError)
 		return
 	}
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"example.com/m/db"
+	"example.com/m/i18n"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx"
 	"golang.org/x/crypto/bcrypt"
@@ -36,20 +37,20 @@ type SignupResponse struct {
 // @Router /signup [post]
 func Handler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		i18n.Error(w, r, i18n.MethodNotAllowed, http.StatusMethodNotAllowed)
 		return
 	}
 
 	var req SignupRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		i18n.Error(w, r, i18n.InvalidJSON, http.StatusBadRequest)
 		return
 	}
 
 	// Hash password
 	hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		http.Error(w, "Error hashing password", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.HashPasswordFailed, http.StatusInternalServerError)
 		return
 	}
 
@@ -67,7 +68,7 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 		} else {
 			log.Printf("Non-Postgres error: %+v\n", err)
 		}
-		http.Error(w, "Database error", http.StatusInternalServerError)
+		i18n.Error(w, r, i18n.DatabaseError, http.StatusInternalServerError)
 		return
 	}
 
@@ -5,10 +5,13 @@ import (
 	"log"
 	"os"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-var Conn *pgx.Conn // Exported variable
+// Conn is the shared connection pool. Requests, event handlers and
+// background work all use it at once, so it must be a pool: a single
+// pgx.Conn is not safe for concurrent use.
+var Conn *pgxpool.Pool
 
 func InitDB() {
 	dsn := os.Getenv("NEON_DB_URL")
@@ -17,7 +20,7 @@ func InitDB() {
 	}
 
 	var err error
-	Conn, err = pgx.Connect(context.Background(), dsn)
+	Conn, err = pgxpool.New(context.Background(), dsn)
 	if err != nil {
 		log.Fatalf("Failed to connect to the database: %v", err)
 	}
@@ -29,4 +32,8 @@ func InitDB() {
 	}
 
 	log.Println("Connected to:", version)
+
+	if err := Migrate(context.Background()); err != nil {
+		log.Fatalf("Migrations failed: %v", err)
+	}
 }
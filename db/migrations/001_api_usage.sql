@@ -0,0 +1,7 @@
+-- Upstream API calls per provider per UTC day, used for quota tracking.
+CREATE TABLE IF NOT EXISTS api_usage (
+    provider TEXT    NOT NULL,
+    day      DATE    NOT NULL,
+    calls    INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (provider, day)
+);
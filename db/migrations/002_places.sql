@@ -0,0 +1,22 @@
+-- Normalized catalog of every place we've seen from a provider, so saved
+-- places and visits can be resolved to something meaningful.
+CREATE TABLE IF NOT EXISTS places (
+    place_id      TEXT PRIMARY KEY,
+    provider      TEXT             NOT NULL,
+    name          TEXT             NOT NULL DEFAULT '',
+    latitude      DOUBLE PRECISION NOT NULL,
+    longitude     DOUBLE PRECISION NOT NULL,
+    address_line1 TEXT             NOT NULL DEFAULT '',
+    address_line2 TEXT             NOT NULL DEFAULT '',
+    formatted     TEXT             NOT NULL DEFAULT '',
+    street        TEXT             NOT NULL DEFAULT '',
+    city          TEXT             NOT NULL DEFAULT '',
+    state         TEXT             NOT NULL DEFAULT '',
+    country       TEXT             NOT NULL DEFAULT '',
+    postcode      TEXT             NOT NULL DEFAULT '',
+    categories    TEXT[]           NOT NULL DEFAULT '{}',
+    created_at    TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS places_lat_lon_idx ON places (latitude, longitude);
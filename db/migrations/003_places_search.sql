@@ -0,0 +1,9 @@
+-- Text search over the places catalog: full-text for word-prefix
+-- autocomplete, trigrams for typo-tolerant matching.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS places_search_fts_idx ON places
+    USING GIN (to_tsvector('simple', name || ' ' || city || ' ' || state));
+
+CREATE INDEX IF NOT EXISTS places_name_trgm_idx ON places
+    USING GIN (name gin_trgm_ops);
@@ -0,0 +1,6 @@
+-- Reverse geocoding results keyed by geohash cell.
+CREATE TABLE IF NOT EXISTS reverse_geocode_cache (
+    geohash    TEXT PRIMARY KEY,
+    response   JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
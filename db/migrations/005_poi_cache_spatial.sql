@@ -0,0 +1,5 @@
+-- Let poi_cache be searched by location: circle entries can then be reused
+-- by anyone nearby, not just callers who round to the same key.
+ALTER TABLE poi_cache ADD COLUMN IF NOT EXISTS area_kind TEXT NOT NULL DEFAULT 'circle';
+
+CREATE INDEX IF NOT EXISTS poi_cache_lat_lon_idx ON poi_cache (latitude, longitude);
@@ -0,0 +1,11 @@
+-- One row per published version of an offline region pack. place_hashes maps
+-- place_id to a hash of its content so later versions can be sent as deltas.
+CREATE TABLE IF NOT EXISTS region_packs (
+    region_key   TEXT        NOT NULL,
+    version      INTEGER     NOT NULL,
+    content_hash TEXT        NOT NULL,
+    place_hashes JSONB       NOT NULL,
+    place_count  INTEGER     NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (region_key, version)
+);
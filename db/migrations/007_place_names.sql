@@ -0,0 +1,3 @@
+-- Per-language names for catalog places, keyed by language code
+-- ({"hi": "...", "or": "..."}). name stays the provider's default name.
+ALTER TABLE places ADD COLUMN IF NOT EXISTS names JSONB NOT NULL DEFAULT '{}'::jsonb;
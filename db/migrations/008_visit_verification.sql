@@ -0,0 +1,12 @@
+-- Where the user said they were when logging a visit, and whether that
+-- checked out. Visits logged without a location stay unverified.
+ALTER TABLE user_visit_history
+    ADD COLUMN IF NOT EXISTS latitude            DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS longitude           DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS accuracy_meters     DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS distance_meters     DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS verified            BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS verification_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS user_visit_history_user_time_idx
+    ON user_visit_history (user_uuid, visited_at DESC);
@@ -0,0 +1,6 @@
+-- How a visit was verified: 'gps' (geofence) or 'qr' (on-site code).
+ALTER TABLE user_visit_history ADD COLUMN IF NOT EXISTS verification_method TEXT;
+
+CREATE INDEX IF NOT EXISTS user_visit_history_checkin_idx
+    ON user_visit_history (user_uuid, place_id, visited_at DESC)
+    WHERE verification_method = 'qr';
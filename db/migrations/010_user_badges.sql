@@ -0,0 +1,14 @@
+-- Badges are defined in code (apis/badges); this records who earned which.
+-- The unique index is what makes awarding idempotent.
+CREATE TABLE IF NOT EXISTS user_badges (
+    user_uuid  UUID        NOT NULL,
+    badge_key  TEXT        NOT NULL,
+    awarded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE user_badges
+    ADD COLUMN IF NOT EXISTS badge_key  TEXT,
+    ADD COLUMN IF NOT EXISTS awarded_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_badges_user_badge_idx
+    ON user_badges (user_uuid, badge_key);
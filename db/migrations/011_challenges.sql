@@ -0,0 +1,33 @@
+-- Admin-defined challenges: visit goal distinct places (any of place_ids,
+-- or in any of categories; anywhere when both are empty) between starts_at
+-- and ends_at.
+CREATE TABLE IF NOT EXISTS challenges (
+    id            BIGSERIAL   PRIMARY KEY,
+    title         TEXT        NOT NULL,
+    description   TEXT        NOT NULL DEFAULT '',
+    goal          INTEGER     NOT NULL CHECK (goal > 0),
+    place_ids     TEXT[]      NOT NULL DEFAULT '{}',
+    categories    TEXT[]      NOT NULL DEFAULT '{}',
+    verified_only BOOLEAN     NOT NULL DEFAULT TRUE,
+    starts_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ends_at       TIMESTAMPTZ,
+    created_by    UUID,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One row per user per challenge they joined. status moves
+-- pending -> in_progress -> completed, or to expired when time runs out.
+CREATE TABLE IF NOT EXISTS user_challenges (
+    user_uuid UUID NOT NULL,
+    status    TEXT NOT NULL DEFAULT 'pending'
+);
+
+ALTER TABLE user_challenges
+    ADD COLUMN IF NOT EXISTS challenge_id BIGINT REFERENCES challenges(id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS progress     INTEGER     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS enrolled_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_challenges_user_challenge_idx
+    ON user_challenges (user_uuid, challenge_id);
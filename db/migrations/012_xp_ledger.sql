@@ -0,0 +1,13 @@
+-- Every XP grant, one row each. (user_uuid, reason, ref) is unique so the
+-- same action is never paid twice.
+CREATE TABLE IF NOT EXISTS xp_ledger (
+    id         BIGSERIAL   PRIMARY KEY,
+    user_uuid  UUID        NOT NULL,
+    reason     TEXT        NOT NULL,
+    ref        TEXT        NOT NULL,
+    points     INTEGER     NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_uuid, reason, ref)
+);
+
+CREATE INDEX IF NOT EXISTS xp_ledger_created_idx ON xp_ledger (created_at);
@@ -0,0 +1,61 @@
+-- Running leaderboard totals, kept up to date as events happen. metric is
+-- xp, visits (distinct places with a verified visit, counted when first
+-- visited) or badges; period is 'all', 'w:<ISO week>' like
+-- 'w:2026-W43', or 'm:<month>' like 'm:2026-10' (UTC).
+CREATE TABLE IF NOT EXISTS leaderboard_scores (
+    metric     TEXT        NOT NULL,
+    period     TEXT        NOT NULL,
+    user_uuid  UUID        NOT NULL,
+    score      BIGINT      NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (metric, period, user_uuid)
+);
+
+CREATE INDEX IF NOT EXISTS leaderboard_scores_rank_idx
+    ON leaderboard_scores (metric, period, score DESC);
+
+-- Distinct places with a verified visit per user per state; a user's region
+-- is where they have the most.
+CREATE TABLE IF NOT EXISTS user_regions (
+    user_uuid UUID    NOT NULL,
+    region    TEXT    NOT NULL,
+    visits    INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_uuid, region)
+);
+
+CREATE TABLE IF NOT EXISTS user_friends (
+    user_uuid   UUID        NOT NULL,
+    friend_uuid UUID        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_uuid, friend_uuid),
+    CHECK (user_uuid <> friend_uuid)
+);
+
+-- Backfill from what happened before leaderboards existed.
+WITH activity AS (
+    SELECT 'xp' AS metric, user_uuid::uuid, created_at AS at, points::BIGINT AS score FROM xp_ledger
+    UNION ALL
+    SELECT 'visits', user_uuid, at, 1 FROM (
+        SELECT DISTINCT ON (user_uuid, place_id) user_uuid::uuid, visited_at AS at
+        FROM user_visit_history WHERE verified
+        ORDER BY user_uuid, place_id, visited_at
+    ) first_visits
+    UNION ALL
+    SELECT 'badges', user_uuid::uuid, awarded_at, 1 FROM user_badges WHERE badge_key IS NOT NULL
+), periods AS (
+    SELECT metric, 'all' AS period, user_uuid, score FROM activity
+    UNION ALL
+    SELECT metric, 'w:' || to_char(at AT TIME ZONE 'UTC', 'IYYY-"W"IW'), user_uuid, score FROM activity
+    UNION ALL
+    SELECT metric, 'm:' || to_char(at AT TIME ZONE 'UTC', 'YYYY-MM'), user_uuid, score FROM activity
+)
+INSERT INTO leaderboard_scores (metric, period, user_uuid, score)
+SELECT metric, period, user_uuid, SUM(score) FROM periods GROUP BY metric, period, user_uuid
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_regions (user_uuid, region, visits)
+SELECT v.user_uuid::uuid, p.state, COUNT(DISTINCT v.place_id)
+FROM user_visit_history v JOIN places p ON p.place_id = v.place_id
+WHERE v.verified AND p.state <> ''
+GROUP BY 1, 2
+ON CONFLICT DO NOTHING;
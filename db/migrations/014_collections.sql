@@ -0,0 +1,32 @@
+-- Named lists of places. share_token, when set, makes the collection
+-- readable by anyone with the link; collection_shares grants specific users
+-- read access.
+CREATE TABLE IF NOT EXISTS collections (
+    id          BIGSERIAL   PRIMARY KEY,
+    owner_uuid  UUID        NOT NULL,
+    name        TEXT        NOT NULL,
+    description TEXT        NOT NULL DEFAULT '',
+    share_token TEXT        UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS collections_owner_idx ON collections (owner_uuid);
+
+CREATE TABLE IF NOT EXISTS collection_places (
+    collection_id BIGINT      NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    place_id      TEXT        NOT NULL,
+    position      INTEGER     NOT NULL,
+    note          TEXT        NOT NULL DEFAULT '',
+    added_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (collection_id, place_id)
+);
+
+CREATE TABLE IF NOT EXISTS collection_shares (
+    collection_id BIGINT      NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    user_uuid     UUID        NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (collection_id, user_uuid)
+);
+
+CREATE INDEX IF NOT EXISTS collection_shares_user_idx ON collection_shares (user_uuid);
@@ -0,0 +1,27 @@
+-- Trip plans built from saved places: a number of days, each an ordered list
+-- of stops with optional planned start times.
+CREATE TABLE IF NOT EXISTS itineraries (
+    id         BIGSERIAL   PRIMARY KEY,
+    owner_uuid UUID        NOT NULL,
+    name       TEXT        NOT NULL,
+    start_date DATE,
+    days       INTEGER     NOT NULL DEFAULT 1,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS itineraries_owner_idx ON itineraries (owner_uuid);
+
+CREATE TABLE IF NOT EXISTS itinerary_stops (
+    id               BIGSERIAL   PRIMARY KEY,
+    itinerary_id     BIGINT      NOT NULL REFERENCES itineraries(id) ON DELETE CASCADE,
+    day              INTEGER     NOT NULL,
+    position         INTEGER     NOT NULL,
+    place_id         TEXT        NOT NULL,
+    planned_time     TIME,
+    duration_minutes INTEGER,
+    note             TEXT        NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS itinerary_stops_day_idx ON itinerary_stops (itinerary_id, day, position);
@@ -0,0 +1,26 @@
+-- Ratings and reviews of places, one per user and place. places carries the
+-- aggregate so catalog reads don't have to touch reviews.
+CREATE TABLE IF NOT EXISTS place_reviews (
+    id             BIGSERIAL   PRIMARY KEY,
+    place_id       TEXT        NOT NULL,
+    user_uuid      UUID        NOT NULL,
+    rating         SMALLINT    NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    body           TEXT        NOT NULL DEFAULT '',
+    verified_visit BOOLEAN     NOT NULL DEFAULT FALSE,
+    helpful_count  INTEGER     NOT NULL DEFAULT 0,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (place_id, user_uuid)
+);
+
+CREATE INDEX IF NOT EXISTS place_reviews_place_idx ON place_reviews (place_id, created_at DESC, id DESC);
+
+CREATE TABLE IF NOT EXISTS review_votes (
+    review_id  BIGINT      NOT NULL REFERENCES place_reviews(id) ON DELETE CASCADE,
+    user_uuid  UUID        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (review_id, user_uuid)
+);
+
+ALTER TABLE places ADD COLUMN IF NOT EXISTS rating_avg DOUBLE PRECISION;
+ALTER TABLE places ADD COLUMN IF NOT EXISTS rating_count INTEGER NOT NULL DEFAULT 0;
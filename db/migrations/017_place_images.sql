@@ -0,0 +1,4 @@
+-- Image metadata for places, from the provider's OSM tags. The images
+-- themselves stay where they are hosted; offline packs list them so apps
+-- can cache them.
+ALTER TABLE places ADD COLUMN IF NOT EXISTS images JSONB NOT NULL DEFAULT '[]';
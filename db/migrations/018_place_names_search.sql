@@ -0,0 +1,13 @@
+-- Search the per-language names too, so a query in the user's language finds
+-- places whose default name is in another script. place_names_text flattens
+-- names into one string; it is IMMUTABLE so it can be indexed.
+CREATE OR REPLACE FUNCTION place_names_text(names JSONB) RETURNS TEXT
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE
+    AS $$ SELECT COALESCE(string_agg(value, ' '), '') FROM jsonb_each_text(names) $$;
+
+DROP INDEX IF EXISTS places_search_fts_idx;
+CREATE INDEX IF NOT EXISTS places_search_names_fts_idx ON places
+    USING GIN (to_tsvector('simple', name || ' ' || place_names_text(names) || ' ' || city || ' ' || state));
+
+CREATE INDEX IF NOT EXISTS places_names_trgm_idx ON places
+    USING GIN (place_names_text(names) gin_trgm_ops);
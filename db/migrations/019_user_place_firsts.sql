@@ -0,0 +1,15 @@
+-- The first verified visit per user and place. Inserting here with
+-- ON CONFLICT DO NOTHING is how leaderboards decide, atomically, that a visit
+-- is the user's first to the place.
+CREATE TABLE IF NOT EXISTS user_place_firsts (
+    user_uuid  UUID        NOT NULL,
+    place_id   TEXT        NOT NULL,
+    visited_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_uuid, place_id)
+);
+
+INSERT INTO user_place_firsts (user_uuid, place_id, visited_at)
+SELECT DISTINCT ON (user_uuid, place_id) user_uuid::uuid, place_id, visited_at
+FROM user_visit_history WHERE verified
+ORDER BY user_uuid, place_id, visited_at
+ON CONFLICT DO NOTHING;
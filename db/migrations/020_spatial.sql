@@ -0,0 +1,24 @@
+-- Generated geography columns and GiST indexes for PostGIS-backed nearby
+-- queries. PostGIS is optional: where it isn't installed on the server, or we
+-- may not create it, this does nothing and the app keeps using its lat/lon
+-- fallback.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'postgis') THEN
+        RAISE NOTICE 'PostGIS is not available; skipping spatial columns';
+        RETURN;
+    END IF;
+
+    CREATE EXTENSION IF NOT EXISTS postgis;
+
+    EXECUTE $sql$ALTER TABLE places ADD COLUMN IF NOT EXISTS geog geography(Point, 4326)
+        GENERATED ALWAYS AS (ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography) STORED$sql$;
+    EXECUTE 'CREATE INDEX IF NOT EXISTS places_geog_idx ON places USING GIST (geog)';
+
+    EXECUTE $sql$ALTER TABLE poi_cache ADD COLUMN IF NOT EXISTS geog geography(Point, 4326)
+        GENERATED ALWAYS AS (ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography) STORED$sql$;
+    EXECUTE 'CREATE INDEX IF NOT EXISTS poi_cache_geog_idx ON poi_cache USING GIST (geog)';
+EXCEPTION WHEN insufficient_privilege THEN
+    RAISE NOTICE 'Not allowed to create PostGIS; skipping spatial columns';
+END
+$$;
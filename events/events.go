@@ -0,0 +1,73 @@
+// Package events lets features react to user activity (visits, saves,
+// awards) without the code recording that activity knowing about them.
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Kind string
+
+const (
+	Visit              Kind = "visit"
+	Save               Kind = "save"
+	BadgeAwarded       Kind = "badge_awarded"
+	ChallengeCompleted Kind = "challenge_completed"
+	Review             Kind = "review"
+	XPGranted          Kind = "xp_granted"
+)
+
+type Event struct {
+	Kind     Kind
+	UserUUID string
+	// PlaceID is set for visits, saves and reviews.
+	PlaceID string
+	// Verified marks a visit that passed location or QR verification, or a
+	// review written after such a visit.
+	Verified bool
+	// Method is how a visit was verified (gps or qr), if it was.
+	Method string
+	// Ref names what was awarded, completed or written (a badge or challenge
+	// key, or a review ID).
+	Ref string
+	// Points is the XP granted, for XPGranted.
+	Points int
+	At     time.Time
+}
+
+type Handler func(ctx context.Context, e Event)
+
+var (
+	mu       sync.RWMutex
+	handlers = map[Kind][]Handler{}
+)
+
+// Subscribe registers h for events of kind. Call it during startup.
+func Subscribe(kind Kind, h Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers[kind] = append(handlers[kind], h)
+}
+
+// Publish runs every handler for e.Kind in order, on the caller's goroutine,
+// so handlers see the database as the caller left it. Handlers log their own
+// failures; a failing handler never fails the request that published.
+// Handlers run detached from ctx's cancellation, so a client hanging up
+// after its activity is recorded can't leave XP, badges or leaderboards
+// half updated.
+func Publish(ctx context.Context, e Event) {
+	ctx = context.WithoutCancel(ctx)
+	if e.At.IsZero() {
+		e.At = time.Now()
+	}
+
+	mu.RLock()
+	hs := handlers[e.Kind]
+	mu.RUnlock()
+
+	for _, h := range hs {
+		h(ctx, e)
+	}
+}
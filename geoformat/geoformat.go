@@ -0,0 +1,207 @@
+// Package geoformat renders lists of points as GeoJSON, GPX or KML so clients
+// can drop results straight into map tools, and picks the format from the
+// request.
+package geoformat
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Format int
+
+const (
+	JSON Format = iota // the endpoint's own JSON shape
+	GeoJSON
+	GPX
+	KML
+)
+
+var mediaTypes = map[Format]string{
+	JSON:    "application/json",
+	GeoJSON: "application/geo+json",
+	GPX:     "application/gpx+xml",
+	KML:     "application/vnd.google-earth.kml+xml",
+}
+
+func (f Format) MediaType() string { return mediaTypes[f] }
+
+// Negotiate picks the output format. An explicit ?format=geojson|gpx|kml|json
+// wins; otherwise the Accept header is honoured by q-value, defaulting to
+// JSON.
+func Negotiate(r *http.Request) Format {
+	switch strings.ToLower(r.URL.Query().Get("format")) {
+	case "geojson":
+		return GeoJSON
+	case "gpx":
+		return GPX
+	case "kml":
+		return KML
+	case "json":
+		return JSON
+	}
+
+	best, bestQ := JSON, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		fields := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(fields[0]))
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		for f, mt := range mediaTypes {
+			if mt == mediaType && q > bestQ {
+				best, bestQ = f, q
+			}
+		}
+		// application/gpx+xml is also commonly requested as application/gpx.
+		if mediaType == "application/gpx" && q > bestQ {
+			best, bestQ = GPX, q
+		}
+	}
+	return best
+}
+
+// Feature is one point to render.
+type Feature struct {
+	ID          string
+	Name        string
+	Lat         float64
+	Lon         float64
+	Description string
+	Categories  []string
+	Time        *time.Time
+	// Properties is the full record, used as the GeoJSON feature properties.
+	Properties any
+}
+
+// Write renders features in f (which must not be JSON). title names the
+// GPX/KML document.
+func Write(w http.ResponseWriter, f Format, title string, features []Feature) error {
+	w.Header().Set("Content-Type", f.MediaType())
+
+	switch f {
+	case GeoJSON:
+		return writeGeoJSON(w, features)
+	case GPX:
+		return writeGPX(w, title, features)
+	case KML:
+		return writeKML(w, title, features)
+	}
+	return fmt.Errorf("geoformat: cannot write format %d", f)
+}
+
+type geoJSONFeature struct {
+	Type       string `json:"type"`
+	ID         string `json:"id,omitempty"`
+	Geometry   any    `json:"geometry"`
+	Properties any    `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+func writeGeoJSON(w io.Writer, features []Feature) error {
+	out := struct {
+		Type     string           `json:"type"`
+		Features []geoJSONFeature `json:"features"`
+	}{Type: "FeatureCollection", Features: make([]geoJSONFeature, 0, len(features))}
+
+	for _, f := range features {
+		props := f.Properties
+		if props == nil {
+			props = map[string]any{"name": f.Name}
+		}
+		out.Features = append(out.Features, geoJSONFeature{
+			Type:       "Feature",
+			ID:         f.ID,
+			Geometry:   geoJSONPoint{Type: "Point", Coordinates: [2]float64{f.Lon, f.Lat}},
+			Properties: props,
+		})
+	}
+	return json.NewEncoder(w).Encode(out)
+}
+
+type gpxWaypoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Time string  `xml:"time,omitempty"`
+	Name string  `xml:"name"`
+	Desc string  `xml:"desc,omitempty"`
+	Type string  `xml:"type,omitempty"`
+}
+
+func writeGPX(w io.Writer, title string, features []Feature) error {
+	doc := struct {
+		XMLName   xml.Name      `xml:"gpx"`
+		Version   string        `xml:"version,attr"`
+		Creator   string        `xml:"creator,attr"`
+		Xmlns     string        `xml:"xmlns,attr"`
+		Name      string        `xml:"metadata>name"`
+		Waypoints []gpxWaypoint `xml:"wpt"`
+	}{Version: "1.1", Creator: "epocheye", Xmlns: "http://www.topografix.com/GPX/1/1", Name: title}
+
+	for _, f := range features {
+		wpt := gpxWaypoint{Lat: f.Lat, Lon: f.Lon, Name: f.Name, Desc: f.Description}
+		if f.Time != nil {
+			wpt.Time = f.Time.UTC().Format(time.RFC3339)
+		}
+		if len(f.Categories) > 0 {
+			wpt.Type = f.Categories[0]
+		}
+		doc.Waypoints = append(doc.Waypoints, wpt)
+	}
+	return writeXML(w, doc)
+}
+
+type kmlPlacemark struct {
+	Name        string `xml:"name"`
+	Description string `xml:"description,omitempty"`
+	When        string `xml:"TimeStamp>when,omitempty"`
+	Coordinates string `xml:"Point>coordinates"`
+}
+
+func writeKML(w io.Writer, title string, features []Feature) error {
+	doc := struct {
+		XMLName    xml.Name       `xml:"kml"`
+		Xmlns      string         `xml:"xmlns,attr"`
+		Name       string         `xml:"Document>name"`
+		Placemarks []kmlPlacemark `xml:"Document>Placemark"`
+	}{Xmlns: "http://www.opengis.net/kml/2.2", Name: title}
+
+	for _, f := range features {
+		pm := kmlPlacemark{
+			Name:        f.Name,
+			Description: f.Description,
+			Coordinates: strconv.FormatFloat(f.Lon, 'f', -1, 64) + "," + strconv.FormatFloat(f.Lat, 'f', -1, 64),
+		}
+		if f.Time != nil {
+			pm.When = f.Time.UTC().Format(time.RFC3339)
+		}
+		doc.Placemarks = append(doc.Placemarks, pm)
+	}
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(doc)
+}
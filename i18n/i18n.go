@@ -0,0 +1,100 @@
+// Package i18n picks the caller's languages from Accept-Language and
+// localizes API messages through a message catalog.
+package i18n
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLang is used when the caller accepts nothing we have.
+const DefaultLang = "en"
+
+// Languages returns the caller's accepted languages as primary subtags
+// ("hi-IN" becomes "hi"), most preferred first, without duplicates.
+func Languages(r *http.Request) []string {
+	type pref struct {
+		lang string
+		q    float64
+	}
+	var prefs []pref
+
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		lang, _, _ := strings.Cut(tag, "-")
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			prefs = append(prefs, pref{lang, q})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+
+	seen := map[string]bool{}
+	langs := make([]string, 0, len(prefs))
+	for _, p := range prefs {
+		if !seen[p.lang] {
+			seen[p.lang] = true
+			langs = append(langs, p.lang)
+		}
+	}
+	return langs
+}
+
+// Lang returns the caller's most preferred language that the message catalog
+// covers.
+func Lang(r *http.Request) string {
+	for _, lang := range Languages(r) {
+		if supported[lang] {
+			return lang
+		}
+	}
+	return DefaultLang
+}
+
+// T returns the message for key in the caller's language, falling back to
+// English.
+func T(r *http.Request, key Key) string {
+	return Translate(Lang(r), key)
+}
+
+func Translate(lang string, key Key) string {
+	msgs, ok := catalog[key]
+	if !ok {
+		return string(key)
+	}
+	if msg, ok := msgs[lang]; ok {
+		return msg
+	}
+	return msgs[DefaultLang]
+}
+
+// Error replies with the localized message as plain text, like http.Error.
+func Error(w http.ResponseWriter, r *http.Request, key Key, code int) {
+	lang := Lang(r)
+	w.Header().Set("Content-Language", lang)
+	http.Error(w, Translate(lang, key), code)
+}
+
+// JSONError replies with {"error": message}, localized.
+func JSONError(w http.ResponseWriter, r *http.Request, key Key, code int) {
+	lang := Lang(r)
+	body, _ := json.Marshal(map[string]string{"error": Translate(lang, key)})
+
+	w.Header().Set("Content-Language", lang)
+	http.Error(w, string(body), code)
+}
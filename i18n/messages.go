@@ -0,0 +1,577 @@
+package i18n
+
+// Key identifies a message in the catalog.
+type Key string
+
+const (
+	MissingToken       Key = "missing_token"
+	InvalidToken       Key = "invalid_token"
+	AdminRequired      Key = "admin_required"
+	Unauthorized       Key = "unauthorized"
+	MethodNotAllowed   Key = "method_not_allowed"
+	InvalidJSON        Key = "invalid_json"
+	DatabaseError      Key = "database_error"
+	UserNotFound       Key = "user_not_found"
+	UpdateProfile      Key = "update_profile_failed"
+	FileTooBig         Key = "file_too_big"
+	AvatarRequired     Key = "avatar_required"
+	ReadFileFailed     Key = "read_file_failed"
+	UploadAvatarFailed Key = "upload_avatar_failed"
+	GetBadgesFailed    Key = "get_badges_failed"
+	GetChallenges      Key = "get_challenges_failed"
+	InvalidPlaceID     Key = "invalid_place_id"
+	MissingPlaceID     Key = "missing_place_id"
+	PlaceNotFound      Key = "place_not_found"
+	SavePlaceFailed    Key = "save_place_failed"
+	RemovePlaceFailed  Key = "remove_place_failed"
+	SavedPlacesFailed  Key = "saved_places_failed"
+	LogVisitFailed     Key = "log_visit_failed"
+	InvalidLocation    Key = "invalid_location"
+	HistoryFailed      Key = "history_failed"
+	TooManySearches    Key = "too_many_searches"
+	InvalidSort        Key = "invalid_sort"
+	BudgetExhausted    Key = "budget_exhausted"
+	FetchPlacesFailed  Key = "fetch_places_failed"
+	FetchPlaceFailed   Key = "fetch_place_failed"
+	QueryTooShort      Key = "query_too_short"
+	InvalidLatLon      Key = "invalid_lat_lon"
+	SearchFailed       Key = "search_failed"
+	NothingFound       Key = "nothing_found"
+	ReverseFailed      Key = "reverse_failed"
+	UsageFailed        Key = "usage_failed"
+	InvalidTile        Key = "invalid_tile"
+	TileFailed         Key = "tile_failed"
+	RegionOrBBox       Key = "region_or_bbox"
+	RegionRequired     Key = "region_required"
+	UnknownRegion      Key = "unknown_region"
+	InvalidSince       Key = "invalid_since"
+	PackBusy           Key = "pack_busy"
+	RegionTooLarge     Key = "region_too_large"
+	PackFailed         Key = "pack_failed"
+	InvalidQRFormat    Key = "invalid_qr_format"
+	QRNotConfigured    Key = "qr_not_configured"
+	QRFailed           Key = "qr_failed"
+	InvalidQRCode      Key = "invalid_qr_code"
+	ExpiredQRCode      Key = "expired_qr_code"
+	AlreadyCheckedIn   Key = "already_checked_in"
+	CheckinFailed      Key = "checkin_failed"
+	InvalidChallenge   Key = "invalid_challenge"
+	InvalidStatus      Key = "invalid_status"
+	ChallengeNotFound  Key = "challenge_not_found"
+	ChallengeEnded     Key = "challenge_ended"
+	ChallengeFailed    Key = "challenge_failed"
+	InvalidTimezone    Key = "invalid_timezone"
+	GetStatsFailed     Key = "get_stats_failed"
+	InvalidLeaderboard Key = "invalid_leaderboard"
+	NoHomeRegion       Key = "no_home_region"
+	LeaderboardFailed  Key = "leaderboard_failed"
+	InvalidFriend      Key = "invalid_friend"
+	FriendsFailed      Key = "friends_failed"
+	InvalidCollection  Key = "invalid_collection"
+	CollectionNotFound Key = "collection_not_found"
+	NotCollectionOwner Key = "not_collection_owner"
+	NotInCollection    Key = "not_in_collection"
+	InvalidOrder       Key = "invalid_order"
+	InvalidShareUser   Key = "invalid_share_user"
+	CollectionFailed   Key = "collection_failed"
+	TooManyRequests    Key = "too_many_requests"
+	InvalidItinerary   Key = "invalid_itinerary"
+	ItineraryNotFound  Key = "itinerary_not_found"
+	StopNotFound       Key = "stop_not_found"
+	PlaceNotSaved      Key = "place_not_saved"
+	InvalidDay         Key = "invalid_day"
+	DayFull            Key = "day_full"
+	DaysInUse          Key = "days_in_use"
+	InvalidStopOrder   Key = "invalid_stop_order"
+	ItineraryFailed    Key = "itinerary_failed"
+	InvalidReview      Key = "invalid_review"
+	ReviewNotFound     Key = "review_not_found"
+	NotVisited         Key = "not_visited"
+	VisitNotVerified   Key = "visit_not_verified"
+	OwnReview          Key = "own_review"
+	ReviewFailed       Key = "review_failed"
+	InvalidPagination  Key = "invalid_pagination"
+	InvalidArea        Key = "invalid_area"
+	InvalidCluster     Key = "invalid_cluster"
+	InvalidBBox        Key = "invalid_bbox"
+	InvalidRefresh     Key = "invalid_refresh_token"
+	InvalidCredentials Key = "invalid_credentials"
+	TokenFailed        Key = "token_failed"
+	HashPasswordFailed Key = "hash_password_failed"
+)
+
+// supported lists the languages every catalog entry is translated into.
+var supported = map[string]bool{"en": true, "hi": true, "or": true}
+
+var catalog = map[Key]map[string]string{
+	MissingToken: {
+		"en": "missing or invalid token",
+		"hi": "टोकन मौजूद नहीं है या अमान्य है",
+		"or": "ଟୋକନ୍ ନାହିଁ କିମ୍ବା ଅବୈଧ",
+	},
+	InvalidToken: {
+		"en": "invalid or expired token",
+		"hi": "टोकन अमान्य है या उसकी अवधि समाप्त हो गई है",
+		"or": "ଟୋକନ୍ ଅବୈଧ କିମ୍ବା ଏହାର ମିଆଦ ସରିଯାଇଛି",
+	},
+	AdminRequired: {
+		"en": "admin access required",
+		"hi": "व्यवस्थापक पहुँच आवश्यक है",
+		"or": "ପ୍ରଶାସକ ଅନୁମତି ଆବଶ୍ୟକ",
+	},
+	Unauthorized: {
+		"en": "Unauthorized",
+		"hi": "अनधिकृत",
+		"or": "ଅନଧିକୃତ",
+	},
+	MethodNotAllowed: {
+		"en": "Method not allowed",
+		"hi": "यह मेथड अनुमत नहीं है",
+		"or": "ଏହି ମେଥଡ୍ ଅନୁମୋଦିତ ନୁହେଁ",
+	},
+	InvalidJSON: {
+		"en": "Invalid JSON",
+		"hi": "अमान्य JSON",
+		"or": "ଅବୈଧ JSON",
+	},
+	DatabaseError: {
+		"en": "Database error",
+		"hi": "डेटाबेस त्रुटि",
+		"or": "ଡାଟାବେସ୍ ତ୍ରୁଟି",
+	},
+	UserNotFound: {
+		"en": "User not found",
+		"hi": "उपयोगकर्ता नहीं मिला",
+		"or": "ଉପଯୋଗକର୍ତ୍ତା ମିଳିଲେ ନାହିଁ",
+	},
+	UpdateProfile: {
+		"en": "Failed to update profile",
+		"hi": "प्रोफ़ाइल अपडेट नहीं हो सकी",
+		"or": "ପ୍ରୋଫାଇଲ୍ ଅପଡେଟ୍ ହୋଇପାରିଲା ନାହିଁ",
+	},
+	FileTooBig: {
+		"en": "File too big",
+		"hi": "फ़ाइल बहुत बड़ी है",
+		"or": "ଫାଇଲ୍ ବହୁତ ବଡ଼",
+	},
+	AvatarRequired: {
+		"en": "Avatar file required",
+		"hi": "अवतार फ़ाइल आवश्यक है",
+		"or": "ଅବତାର ଫାଇଲ୍ ଆବଶ୍ୟକ",
+	},
+	ReadFileFailed: {
+		"en": "Failed to read file",
+		"hi": "फ़ाइल पढ़ी नहीं जा सकी",
+		"or": "ଫାଇଲ୍ ପଢ଼ିହେଲା ନାହିଁ",
+	},
+	UploadAvatarFailed: {
+		"en": "Failed to upload avatar",
+		"hi": "अवतार अपलोड नहीं हो सका",
+		"or": "ଅବତାର ଅପଲୋଡ୍ ହୋଇପାରିଲା ନାହିଁ",
+	},
+	GetBadgesFailed: {
+		"en": "Failed to get badges",
+		"hi": "बैज प्राप्त नहीं हो सके",
+		"or": "ବ୍ୟାଜ୍ ମିଳିପାରିଲା ନାହିଁ",
+	},
+	GetChallenges: {
+		"en": "Failed to get challenges",
+		"hi": "चुनौतियाँ प्राप्त नहीं हो सकीं",
+		"or": "ଚ୍ୟାଲେଞ୍ଜ ମିଳିପାରିଲା ନାହିଁ",
+	},
+	InvalidPlaceID: {
+		"en": "Invalid place_id",
+		"hi": "अमान्य place_id",
+		"or": "ଅବୈଧ place_id",
+	},
+	MissingPlaceID: {
+		"en": "Missing place_id",
+		"hi": "place_id मौजूद नहीं है",
+		"or": "place_id ନାହିଁ",
+	},
+	PlaceNotFound: {
+		"en": "Place not found",
+		"hi": "स्थान नहीं मिला",
+		"or": "ସ୍ଥାନ ମିଳିଲା ନାହିଁ",
+	},
+	SavePlaceFailed: {
+		"en": "Failed to save place",
+		"hi": "स्थान सहेजा नहीं जा सका",
+		"or": "ସ୍ଥାନ ସେଭ୍ ହୋଇପାରିଲା ନାହିଁ",
+	},
+	RemovePlaceFailed: {
+		"en": "Failed to remove saved place",
+		"hi": "सहेजा गया स्थान हटाया नहीं जा सका",
+		"or": "ସେଭ୍ ହୋଇଥିବା ସ୍ଥାନ ହଟାଇହେଲା ନାହିଁ",
+	},
+	SavedPlacesFailed: {
+		"en": "Failed to fetch saved places",
+		"hi": "सहेजे गए स्थान प्राप्त नहीं हो सके",
+		"or": "ସେଭ୍ ହୋଇଥିବା ସ୍ଥାନ ମିଳିପାରିଲା ନାହିଁ",
+	},
+	LogVisitFailed: {
+		"en": "Failed to log visit",
+		"hi": "यात्रा दर्ज नहीं हो सकी",
+		"or": "ଭ୍ରମଣ ରେକର୍ଡ ହୋଇପାରିଲା ନାହିଁ",
+	},
+	InvalidLocation: {
+		"en": "latitude and longitude must be sent together and be valid, with a non-negative accuracy_meters",
+		"hi": "latitude और longitude दोनों एक साथ और मान्य होने चाहिए, तथा accuracy_meters ऋणात्मक नहीं होना चाहिए",
+		"or": "latitude ଓ longitude ଦୁହେଁ ଏକାସାଙ୍ଗରେ ଓ ବୈଧ ହେବା ଆବଶ୍ୟକ, ଏବଂ accuracy_meters ଋଣାତ୍ମକ ହେବା ଉଚିତ ନୁହେଁ",
+	},
+	HistoryFailed: {
+		"en": "Failed to get history",
+		"hi": "इतिहास प्राप्त नहीं हो सका",
+		"or": "ଇତିହାସ ମିଳିପାରିଲା ନାହିଁ",
+	},
+	TooManySearches: {
+		"en": "Too many searches, slow down",
+		"hi": "बहुत अधिक खोजें, कृपया थोड़ा रुकें",
+		"or": "ଅତ୍ୟଧିକ ସନ୍ଧାନ, ଦୟାକରି ଟିକେ ଅପେକ୍ଷା କରନ୍ତୁ",
+	},
+	InvalidSort: {
+		"en": "sort must be distance, relevance or name",
+		"hi": "sort का मान distance, relevance या name होना चाहिए",
+		"or": "sort ର ମୂଲ୍ୟ distance, relevance କିମ୍ବା name ହେବା ଆବଶ୍ୟକ",
+	},
+	BudgetExhausted: {
+		"en": "Daily search budget exhausted, try again later",
+		"hi": "आज की खोज सीमा समाप्त हो गई है, बाद में पुनः प्रयास करें",
+		"or": "ଆଜିର ସନ୍ଧାନ ସୀମା ସରିଗଲା, ପରେ ପୁଣି ଚେଷ୍ଟା କରନ୍ତୁ",
+	},
+	FetchPlacesFailed: {
+		"en": "Failed fetching places",
+		"hi": "स्थान प्राप्त नहीं हो सके",
+		"or": "ସ୍ଥାନଗୁଡ଼ିକ ଆଣିହେଲା ନାହିଁ",
+	},
+	FetchPlaceFailed: {
+		"en": "Failed fetching place",
+		"hi": "स्थान प्राप्त नहीं हो सका",
+		"or": "ସ୍ଥାନ ଆଣିହେଲା ନାହିଁ",
+	},
+	QueryTooShort: {
+		"en": "q must be at least 2 characters",
+		"hi": "q में कम से कम 2 अक्षर होने चाहिए",
+		"or": "q ରେ ଅତି କମରେ 2ଟି ଅକ୍ଷର ରହିବା ଆବଶ୍ୟକ",
+	},
+	InvalidLatLon: {
+		"en": "Invalid lat/lon",
+		"hi": "अमान्य अक्षांश/देशांतर",
+		"or": "ଅବୈଧ ଅକ୍ଷାଂଶ/ଦ୍ରାଘିମା",
+	},
+	SearchFailed: {
+		"en": "Search failed",
+		"hi": "खोज विफल रही",
+		"or": "ସନ୍ଧାନ ବିଫଳ ହେଲା",
+	},
+	NothingFound: {
+		"en": "Nothing found at this location",
+		"hi": "इस स्थान पर कुछ नहीं मिला",
+		"or": "ଏହି ସ୍ଥାନରେ କିଛି ମିଳିଲା ନାହିଁ",
+	},
+	ReverseFailed: {
+		"en": "Failed reverse geocoding",
+		"hi": "पता खोजा नहीं जा सका",
+		"or": "ଠିକଣା ଖୋଜିହେଲା ନାହିଁ",
+	},
+	UsageFailed: {
+		"en": "Failed to load usage",
+		"hi": "उपयोग विवरण लोड नहीं हो सका",
+		"or": "ବ୍ୟବହାର ବିବରଣୀ ଲୋଡ୍ ହୋଇପାରିଲା ନାହିଁ",
+	},
+	InvalidTile: {
+		"en": "Invalid tile coordinates",
+		"hi": "अमान्य टाइल निर्देशांक",
+		"or": "ଅବୈଧ ଟାଇଲ୍ ସ୍ଥାନାଙ୍କ",
+	},
+	TileFailed: {
+		"en": "Failed to build tile",
+		"hi": "टाइल नहीं बन सकी",
+		"or": "ଟାଇଲ୍ ତିଆରି ହୋଇପାରିଲା ନାହିଁ",
+	},
+	RegionOrBBox: {
+		"en": "Send either region or bbox, not both",
+		"hi": "region या bbox में से केवल एक भेजें",
+		"or": "region କିମ୍ବା bbox ମଧ୍ୟରୁ କେବଳ ଗୋଟିଏ ପଠାନ୍ତୁ",
+	},
+	RegionRequired: {
+		"en": "region or bbox required",
+		"hi": "region या bbox आवश्यक है",
+		"or": "region କିମ୍ବା bbox ଆବଶ୍ୟକ",
+	},
+	UnknownRegion: {
+		"en": "Unknown region",
+		"hi": "अज्ञात क्षेत्र",
+		"or": "ଅଜଣା ଅଞ୍ଚଳ",
+	},
+	InvalidSince: {
+		"en": "Invalid since",
+		"hi": "अमान्य since",
+		"or": "ଅବୈଧ since",
+	},
+	RegionTooLarge: {
+		"en": "Region has too many places for one pack; download it as smaller bounding boxes",
+		"hi": "इस क्षेत्र में एक पैक के लिए बहुत अधिक स्थान हैं; इसे छोटे bounding box में डाउनलोड करें",
+		"or": "ଏହି ଅଞ୍ଚଳରେ ଗୋଟିଏ ପ୍ୟାକ୍ ପାଇଁ ଅତ୍ୟଧିକ ସ୍ଥାନ ଅଛି; ଏହାକୁ ଛୋଟ bounding box ରେ ଡାଉନଲୋଡ୍ କରନ୍ତୁ",
+	},
+	PackBusy: {
+		"en": "Region pack is being updated, try again",
+		"hi": "क्षेत्र पैक अपडेट हो रहा है, पुनः प्रयास करें",
+		"or": "ଅଞ୍ଚଳ ପ୍ୟାକ୍ ଅପଡେଟ୍ ହେଉଛି, ପୁଣି ଚେଷ୍ଟା କରନ୍ତୁ",
+	},
+	PackFailed: {
+		"en": "Failed to build region pack",
+		"hi": "क्षेत्र पैक नहीं बन सका",
+		"or": "ଅଞ୍ଚଳ ପ୍ୟାକ୍ ତିଆରି ହୋଇପାରିଲା ନାହିଁ",
+	},
+	InvalidQRFormat: {
+		"en": "format must be png, svg or json",
+		"hi": "format का मान png, svg या json होना चाहिए",
+		"or": "format ର ମୂଲ୍ୟ png, svg କିମ୍ବା json ହେବା ଆବଶ୍ୟକ",
+	},
+	QRNotConfigured: {
+		"en": "QR check-in is not configured",
+		"hi": "QR चेक-इन कॉन्फ़िगर नहीं है",
+		"or": "QR ଚେକ୍-ଇନ୍ କନଫିଗର୍ ହୋଇନାହିଁ",
+	},
+	QRFailed: {
+		"en": "Failed to generate QR code",
+		"hi": "QR कोड नहीं बन सका",
+		"or": "QR କୋଡ୍ ତିଆରି ହୋଇପାରିଲା ନାହିଁ",
+	},
+	InvalidQRCode: {
+		"en": "Invalid check-in code",
+		"hi": "अमान्य चेक-इन कोड",
+		"or": "ଅବୈଧ ଚେକ୍-ଇନ୍ କୋଡ୍",
+	},
+	ExpiredQRCode: {
+		"en": "Check-in code has expired, scan the code again",
+		"hi": "चेक-इन कोड की अवधि समाप्त हो गई है, कोड फिर से स्कैन करें",
+		"or": "ଚେକ୍-ଇନ୍ କୋଡ୍ ର ମିଆଦ ସରିଯାଇଛି, କୋଡ୍ ପୁଣି ସ୍କାନ୍ କରନ୍ତୁ",
+	},
+	AlreadyCheckedIn: {
+		"en": "Already checked in here recently",
+		"hi": "आप हाल ही में यहाँ चेक-इन कर चुके हैं",
+		"or": "ଆପଣ ନିକଟରେ ଏଠାରେ ଚେକ୍-ଇନ୍ କରିସାରିଛନ୍ତି",
+	},
+	CheckinFailed: {
+		"en": "Failed to check in",
+		"hi": "चेक-इन नहीं हो सका",
+		"or": "ଚେକ୍-ଇନ୍ ହୋଇପାରିଲା ନାହିଁ",
+	},
+	InvalidChallenge: {
+		"en": "title and a positive goal are required, and ends_at must be after starts_at",
+		"hi": "title और धनात्मक goal आवश्यक हैं, तथा ends_at का समय starts_at के बाद होना चाहिए",
+		"or": "title ଓ ଧନାତ୍ମକ goal ଆବଶ୍ୟକ, ଏବଂ ends_at ସମୟ starts_at ପରେ ହେବା ଆବଶ୍ୟକ",
+	},
+	InvalidStatus: {
+		"en": "status must be pending, in_progress, completed or expired",
+		"hi": "status का मान pending, in_progress, completed या expired होना चाहिए",
+		"or": "status ର ମୂଲ୍ୟ pending, in_progress, completed କିମ୍ବା expired ହେବା ଆବଶ୍ୟକ",
+	},
+	ChallengeNotFound: {
+		"en": "Challenge not found",
+		"hi": "चुनौती नहीं मिली",
+		"or": "ଚ୍ୟାଲେଞ୍ଜ ମିଳିଲା ନାହିଁ",
+	},
+	ChallengeEnded: {
+		"en": "Challenge has ended",
+		"hi": "चुनौती समाप्त हो चुकी है",
+		"or": "ଚ୍ୟାଲେଞ୍ଜ ଶେଷ ହୋଇଯାଇଛି",
+	},
+	ChallengeFailed: {
+		"en": "Failed to update challenge",
+		"hi": "चुनौती अपडेट नहीं हो सकी",
+		"or": "ଚ୍ୟାଲେଞ୍ଜ ଅପଡେଟ୍ ହୋଇପାରିଲା ନାହିଁ",
+	},
+	InvalidTimezone: {
+		"en": "tz must be an IANA timezone such as Asia/Kolkata",
+		"hi": "tz एक IANA टाइमज़ोन होना चाहिए, जैसे Asia/Kolkata",
+		"or": "tz ଏକ IANA ଟାଇମଜୋନ୍ ହେବା ଆବଶ୍ୟକ, ଯେପରି Asia/Kolkata",
+	},
+	GetStatsFailed: {
+		"en": "Failed to get stats",
+		"hi": "आँकड़े प्राप्त नहीं हो सके",
+		"or": "ପରିସଂଖ୍ୟାନ ମିଳିପାରିଲା ନାହିଁ",
+	},
+	InvalidLeaderboard: {
+		"en": "metric must be xp, visits or badges; window weekly, monthly or all; scope global, region or friends",
+		"hi": "metric का मान xp, visits या badges; window का weekly, monthly या all; तथा scope का global, region या friends होना चाहिए",
+		"or": "metric ର ମୂଲ୍ୟ xp, visits କିମ୍ବା badges; window ର weekly, monthly କିମ୍ବା all; ଏବଂ scope ର global, region କିମ୍ବା friends ହେବା ଆବଶ୍ୟକ",
+	},
+	NoHomeRegion: {
+		"en": "region is required until you have a verified visit",
+		"hi": "सत्यापित यात्रा होने तक region आवश्यक है",
+		"or": "ଯାଞ୍ଚ ହୋଇଥିବା ଭ୍ରମଣ ନହେବା ପର୍ଯ୍ୟନ୍ତ region ଆବଶ୍ୟକ",
+	},
+	LeaderboardFailed: {
+		"en": "Failed to load leaderboard",
+		"hi": "लीडरबोर्ड लोड नहीं हो सका",
+		"or": "ଲିଡରବୋର୍ଡ ଲୋଡ୍ ହୋଇପାରିଲା ନାହିଁ",
+	},
+	InvalidFriend: {
+		"en": "friend_uuid must be another user's UUID",
+		"hi": "friend_uuid किसी अन्य उपयोगकर्ता का UUID होना चाहिए",
+		"or": "friend_uuid ଅନ୍ୟ ଜଣେ ଉପଯୋଗକର୍ତ୍ତାଙ୍କ UUID ହେବା ଆବଶ୍ୟକ",
+	},
+	FriendsFailed: {
+		"en": "Failed to update friends",
+		"hi": "मित्र सूची अपडेट नहीं हो सकी",
+		"or": "ବନ୍ଧୁ ତାଲିକା ଅପଡେଟ୍ ହୋଇପାରିଲା ନାହିଁ",
+	},
+	InvalidCollection: {
+		"en": "name must be 1 to 100 characters, description and note at most 1000",
+		"hi": "name 1 से 100 अक्षरों का, तथा description और note अधिकतम 1000 अक्षरों के होने चाहिए",
+		"or": "name 1 ରୁ 100 ଅକ୍ଷର, ଏବଂ description ଓ note ସର୍ବାଧିକ 1000 ଅକ୍ଷର ହେବା ଆବଶ୍ୟକ",
+	},
+	CollectionNotFound: {
+		"en": "Collection not found",
+		"hi": "संग्रह नहीं मिला",
+		"or": "ସଂଗ୍ରହ ମିଳିଲା ନାହିଁ",
+	},
+	NotCollectionOwner: {
+		"en": "Only the owner can change this collection",
+		"hi": "केवल स्वामी ही इस संग्रह को बदल सकता है",
+		"or": "କେବଳ ମାଲିକ ଏହି ସଂଗ୍ରହକୁ ବଦଳାଇପାରିବେ",
+	},
+	NotInCollection: {
+		"en": "Place is not in this collection",
+		"hi": "यह स्थान इस संग्रह में नहीं है",
+		"or": "ଏହି ସ୍ଥାନ ଏହି ସଂଗ୍ରହରେ ନାହିଁ",
+	},
+	InvalidOrder: {
+		"en": "place_ids must list every place in the collection exactly once",
+		"hi": "place_ids में संग्रह का हर स्थान ठीक एक बार होना चाहिए",
+		"or": "place_ids ରେ ସଂଗ୍ରହର ପ୍ରତ୍ୟେକ ସ୍ଥାନ ଠିକ୍ ଥରେ ରହିବା ଆବଶ୍ୟକ",
+	},
+	InvalidShareUser: {
+		"en": "user_uuid must be another user's UUID",
+		"hi": "user_uuid किसी अन्य उपयोगकर्ता का UUID होना चाहिए",
+		"or": "user_uuid ଅନ୍ୟ ଜଣେ ଉପଯୋଗକର୍ତ୍ତାଙ୍କ UUID ହେବା ଆବଶ୍ୟକ",
+	},
+	CollectionFailed: {
+		"en": "Failed to update collection",
+		"hi": "संग्रह अपडेट नहीं हो सका",
+		"or": "ସଂଗ୍ରହ ଅପଡେଟ୍ ହୋଇପାରିଲା ନାହିଁ",
+	},
+	TooManyRequests: {
+		"en": "Too many requests, slow down",
+		"hi": "बहुत अधिक अनुरोध, कृपया थोड़ा रुकें",
+		"or": "ଅତ୍ୟଧିକ ଅନୁରୋଧ, ଦୟାକରି ଟିକେ ଅପେକ୍ଷା କରନ୍ତୁ",
+	},
+	InvalidItinerary: {
+		"en": "name must be 1 to 100 characters, start_date YYYY-MM-DD, days 1 to 30, planned_time HH:MM, duration_minutes 0 to 1440 and note at most 1000 characters",
+		"hi": "name 1 से 100 अक्षरों का, start_date YYYY-MM-DD, days 1 से 30, planned_time HH:MM, duration_minutes 0 से 1440 तथा note अधिकतम 1000 अक्षरों का होना चाहिए",
+		"or": "name 1 ରୁ 100 ଅକ୍ଷର, start_date YYYY-MM-DD, days 1 ରୁ 30, planned_time HH:MM, duration_minutes 0 ରୁ 1440 ଏବଂ note ସର୍ବାଧିକ 1000 ଅକ୍ଷର ହେବା ଆବଶ୍ୟକ",
+	},
+	ItineraryNotFound: {
+		"en": "Itinerary not found",
+		"hi": "यात्रा योजना नहीं मिली",
+		"or": "ଯାତ୍ରା ଯୋଜନା ମିଳିଲା ନାହିଁ",
+	},
+	StopNotFound: {
+		"en": "Stop not found",
+		"hi": "पड़ाव नहीं मिला",
+		"or": "ବିରତି ସ୍ଥଳ ମିଳିଲା ନାହିଁ",
+	},
+	PlaceNotSaved: {
+		"en": "Save the place before adding it to an itinerary",
+		"hi": "यात्रा योजना में जोड़ने से पहले स्थान को सहेजें",
+		"or": "ଯାତ୍ରା ଯୋଜନାରେ ଯୋଡିବା ପୂର୍ବରୁ ସ୍ଥାନଟିକୁ ସେଭ୍ କରନ୍ତୁ",
+	},
+	InvalidDay: {
+		"en": "day must be one of the itinerary's days",
+		"hi": "day यात्रा योजना के दिनों में से एक होना चाहिए",
+		"or": "day ଯାତ୍ରା ଯୋଜନାର ଦିନଗୁଡ଼ିକ ମଧ୍ୟରୁ ଗୋଟିଏ ହେବା ଆବଶ୍ୟକ",
+	},
+	DayFull: {
+		"en": "A day can have at most 50 stops",
+		"hi": "एक दिन में अधिकतम 50 पड़ाव हो सकते हैं",
+		"or": "ଗୋଟିଏ ଦିନରେ ସର୍ବାଧିକ 50ଟି ବିରତି ସ୍ଥଳ ରହିପାରିବ",
+	},
+	DaysInUse: {
+		"en": "Move or remove the stops on later days first",
+		"hi": "पहले बाद के दिनों के पड़ाव हटाएँ या स्थानांतरित करें",
+		"or": "ପ୍ରଥମେ ପରବର୍ତ୍ତୀ ଦିନଗୁଡ଼ିକର ବିରତି ସ୍ଥଳ ହଟାନ୍ତୁ କିମ୍ବା ସ୍ଥାନାନ୍ତର କରନ୍ତୁ",
+	},
+	InvalidStopOrder: {
+		"en": "stop_ids must list every stop on the day exactly once",
+		"hi": "stop_ids में उस दिन का हर पड़ाव ठीक एक बार होना चाहिए",
+		"or": "stop_ids ରେ ସେହି ଦିନର ପ୍ରତ୍ୟେକ ବିରତି ସ୍ଥଳ ଠିକ୍ ଥରେ ରହିବା ଆବଶ୍ୟକ",
+	},
+	ItineraryFailed: {
+		"en": "Failed to update itinerary",
+		"hi": "यात्रा योजना अपडेट नहीं हो सकी",
+		"or": "ଯାତ୍ରା ଯୋଜନା ଅପଡେଟ୍ ହୋଇପାରିଲା ନାହିଁ",
+	},
+	InvalidReview: {
+		"en": "rating must be 1 to 5 and body at most 2000 characters",
+		"hi": "rating 1 से 5 के बीच तथा body अधिकतम 2000 अक्षरों का होना चाहिए",
+		"or": "rating 1 ରୁ 5 ମଧ୍ୟରେ ଏବଂ body ସର୍ବାଧିକ 2000 ଅକ୍ଷର ହେବା ଆବଶ୍ୟକ",
+	},
+	ReviewNotFound: {
+		"en": "Review not found",
+		"hi": "समीक्षा नहीं मिली",
+		"or": "ସମୀକ୍ଷା ମିଳିଲା ନାହିଁ",
+	},
+	NotVisited: {
+		"en": "Log a visit to this place before reviewing it",
+		"hi": "समीक्षा करने से पहले इस स्थान की यात्रा दर्ज करें",
+		"or": "ସମୀକ୍ଷା କରିବା ପୂର୍ବରୁ ଏହି ସ୍ଥାନର ଭ୍ରମଣ ଲଗ୍ କରନ୍ତୁ",
+	},
+	VisitNotVerified: {
+		"en": "Only places you have a verified visit to can be reviewed",
+		"hi": "केवल उन्हीं स्थानों की समीक्षा की जा सकती है जहाँ आपकी सत्यापित यात्रा हो",
+		"or": "କେବଳ ଯାଞ୍ଚ ହୋଇଥିବା ଭ୍ରମଣ ଥିବା ସ୍ଥାନର ସମୀକ୍ଷା କରାଯାଇପାରିବ",
+	},
+	OwnReview: {
+		"en": "You can't vote on your own review",
+		"hi": "आप अपनी समीक्षा पर वोट नहीं कर सकते",
+		"or": "ଆପଣ ନିଜ ସମୀକ୍ଷାରେ ଭୋଟ୍ ଦେଇପାରିବେ ନାହିଁ",
+	},
+	ReviewFailed: {
+		"en": "Failed to update review",
+		"hi": "समीक्षा अपडेट नहीं हो सकी",
+		"or": "ସମୀକ୍ଷା ଅପଡେଟ୍ ହୋଇପାରିଲା ନାହିଁ",
+	},
+	InvalidPagination: {
+		"en": "limit, cursor, order, from or to is invalid",
+		"hi": "limit, cursor, order, from या to अमान्य है",
+		"or": "limit, cursor, order, from କିମ୍ବା to ଅବୈଧ",
+	},
+	InvalidArea: {
+		"en": "Send latitude and longitude, a valid bbox or a valid polygon, but not both bbox and polygon",
+		"hi": "latitude और longitude, मान्य bbox या मान्य polygon भेजें, पर bbox और polygon दोनों नहीं",
+		"or": "latitude ଓ longitude, ବୈଧ bbox କିମ୍ବା ବୈଧ polygon ପଠାନ୍ତୁ, କିନ୍ତୁ bbox ଓ polygon ଦୁହେଁ ନୁହେଁ",
+	},
+	InvalidCluster: {
+		"en": "cluster zoom must be 0 to 22, method grid or dbscan, and radius_px 1 to 512",
+		"hi": "cluster का zoom 0 से 22, method grid या dbscan, तथा radius_px 1 से 512 होना चाहिए",
+		"or": "cluster ର zoom 0 ରୁ 22, method grid କିମ୍ବା dbscan, ଏବଂ radius_px 1 ରୁ 512 ହେବା ଆବଶ୍ୟକ",
+	},
+	InvalidBBox: {
+		"en": "bbox must be west,south,east,north and span at most 10 degrees",
+		"hi": "bbox west,south,east,north के रूप में और अधिकतम 10 डिग्री का होना चाहिए",
+		"or": "bbox west,south,east,north ରୂପରେ ଏବଂ ସର୍ବାଧିକ 10 ଡିଗ୍ରୀ ହେବା ଆବଶ୍ୟକ",
+	},
+	InvalidRefresh: {
+		"en": "invalid refresh token",
+		"hi": "रिफ्रेश टोकन अमान्य है",
+		"or": "ରିଫ୍ରେସ୍ ଟୋକନ୍ ଅବୈଧ",
+	},
+	InvalidCredentials: {
+		"en": "Invalid email or password",
+		"hi": "ईमेल या पासवर्ड गलत है",
+		"or": "ଇମେଲ୍ କିମ୍ବା ପାସୱାର୍ଡ ଭୁଲ୍",
+	},
+	TokenFailed: {
+		"en": "Could not generate tokens",
+		"hi": "टोकन नहीं बनाए जा सके",
+		"or": "ଟୋକନ୍ ତିଆରି ହୋଇପାରିଲା ନାହିଁ",
+	},
+	HashPasswordFailed: {
+		"en": "Could not secure the password",
+		"hi": "पासवर्ड सुरक्षित नहीं किया जा सका",
+		"or": "ପାସୱାର୍ଡ ସୁରକ୍ଷିତ କରାଯାଇପାରିଲା ନାହିଁ",
+	},
+}
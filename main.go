@@ -1,12 +1,22 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"os"
+	_ "time/tzdata" // Streaks need IANA timezones even where the OS has none
 
+	"example.com/m/apis/badges"
+	"example.com/m/apis/challenges"
+	"example.com/m/apis/checkin"
 	"example.com/m/apis/findplaces"
+	"example.com/m/apis/leaderboards"
+	"example.com/m/apis/offline"
+	"example.com/m/apis/tiles"
+	"example.com/m/apis/useractions"
 	"example.com/m/apis/users"
+	"example.com/m/apis/xp"
 	"example.com/m/auth"
 	"example.com/m/auth/login"
 	"example.com/m/auth/signup"
@@ -55,6 +65,11 @@ func main() {
 
 	// ✅ Initialize PostgreSQL
 	db.InitDB()
+	findplaces.InitSpatial(context.Background(), db.Conn)
+	badges.Init()
+	challenges.Init()
+	xp.Init()
+	leaderboards.Init()
 
 	// ✅ Initialize Redis
 	// findplaces.InitRedis()
@@ -71,9 +86,21 @@ func main() {
 
 	// ✅ Find Places API route (protected by middleware)
 	mux.Handle("/findplaces", middleware.Auth(http.HandlerFunc(findplaces.Handler)))
+	mux.Handle("/places/", http.StripPrefix("/places", findplaces.Routes()))
+	mux.Handle("/tiles/", http.StripPrefix("/tiles", tiles.Routes()))
+	mux.Handle("/offline/", http.StripPrefix("/offline", offline.Routes()))
+	mux.Handle("/geo/reverse", middleware.Auth(http.HandlerFunc(findplaces.ReverseHandler)))
+
+	// Admin routes
+	mux.Handle("/admin/usage", middleware.Auth(middleware.Admin(http.HandlerFunc(findplaces.UsageHandler))))
+	mux.Handle("/admin/places/", http.StripPrefix("/admin/places", checkin.AdminRoutes()))
+	mux.Handle("/admin/challenges", http.StripPrefix("/admin/challenges", challenges.AdminRoutes()))
 
 	mux.Handle("/api/user/", http.StripPrefix("/api/user", users.Routes()))
 
+	// Public, read-only share links
+	mux.Handle("/shared/", http.StripPrefix("/shared", useractions.SharedRoutes()))
+
 	log.Println("🚀 Server running on http://localhost:8080")
 	log.Fatal(http.ListenAndServe(":8080", mux))
 }
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"example.com/m/i18n"
+)
+
+// Admin only lets through users listed in ADMIN_USER_UUIDS (comma separated).
+// It must be chained after Auth, which puts the caller's UUID in the context.
+func Admin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userUUID, _ := r.Context().Value(UserUUIDKey).(string)
+		if userUUID == "" {
+			i18n.Error(w, r, i18n.MissingToken, http.StatusUnauthorized)
+			return
+		}
+
+		if !IsAdmin(userUUID) {
+			i18n.Error(w, r, i18n.AdminRequired, http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func IsAdmin(userUUID string) bool {
+	for _, id := range strings.Split(os.Getenv("ADMIN_USER_UUIDS"), ",") {
+		if strings.TrimSpace(id) == userUUID {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"example.com/m/auth"
+	"example.com/m/i18n"
 )
 
 type ctxKey string
@@ -20,7 +21,7 @@ func Auth(next http.Handler) http.Handler {
 
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			http.Error(w, "missing or invalid token", http.StatusUnauthorized)
+			i18n.Error(w, r, i18n.MissingToken, http.StatusUnauthorized)
 			return
 		}
 
@@ -29,27 +30,27 @@ func Auth(next http.Handler) http.Handler {
 		// Validate JWT
 		claims, err := auth.ValidateJWT(token)
 		if err != nil {
-			http.Error(w, "invalid or expired token", http.StatusUnauthorized)
+			i18n.Error(w, r, i18n.InvalidToken, http.StatusUnauthorized)
 			return
 		}
 
 		// Extract email
 		email, ok := claims["email"].(string)
 		if !ok || email == "" {
-			http.Error(w, "token missing email", http.StatusUnauthorized)
+			i18n.Error(w, r, i18n.InvalidToken, http.StatusUnauthorized)
 			return
 		}
 
 		// Extract UUID properly as string
 		rawUUID, ok := claims["user_uuid"]
 		if !ok {
-			http.Error(w, "token missing uuid", http.StatusUnauthorized)
+			i18n.Error(w, r, i18n.InvalidToken, http.StatusUnauthorized)
 			return
 		}
 
 		userUUID, ok := rawUUID.(string)
 		if !ok || userUUID == "" {
-			http.Error(w, "invalid uuid in token", http.StatusUnauthorized)
+			i18n.Error(w, r, i18n.InvalidToken, http.StatusUnauthorized)
 			return
 		}
 
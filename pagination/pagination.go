@@ -0,0 +1,165 @@
+// Package pagination implements keyset pagination shared by list endpoints:
+// an opaque cursor, a page size, a sort order over a timestamp column and an
+// optional date range.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the last row of a page. ID breaks ties between rows that share
+// a timestamp.
+type Cursor struct {
+	Time time.Time `json:"t"`
+	ID   string    `json:"id"`
+	Desc bool      `json:"d"`
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// Page is a parsed page request.
+type Page struct {
+	Limit int
+	Desc  bool
+	After *Cursor
+	From  *time.Time
+	To    *time.Time
+}
+
+// FromRequest reads limit, cursor, order (asc|desc, default desc), from and
+// to (RFC 3339 or YYYY-MM-DD) from the query string.
+func FromRequest(r *http.Request) (Page, error) {
+	q := r.URL.Query()
+	page := Page{Limit: DefaultLimit, Desc: true}
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return Page{}, fmt.Errorf("invalid limit %q", v)
+		}
+		page.Limit = min(n, MaxLimit)
+	}
+
+	switch strings.ToLower(q.Get("order")) {
+	case "", "desc":
+	case "asc":
+		page.Desc = false
+	default:
+		return Page{}, fmt.Errorf("invalid order %q", q.Get("order"))
+	}
+
+	if v := q.Get("cursor"); v != "" {
+		c, err := DecodeCursor(v)
+		if err != nil {
+			return Page{}, err
+		}
+		if c.Desc != page.Desc {
+			return Page{}, errors.New("cursor does not match sort order")
+		}
+		page.After = &c
+	}
+
+	var err error
+	if page.From, err = parseTime(q.Get("from"), false); err != nil {
+		return Page{}, fmt.Errorf("invalid from: %w", err)
+	}
+	if page.To, err = parseTime(q.Get("to"), true); err != nil {
+		return Page{}, fmt.Errorf("invalid to: %w", err)
+	}
+
+	return page, nil
+}
+
+// parseTime accepts RFC 3339 or a bare date. A bare date used as an upper
+// bound covers the whole day.
+func parseTime(v string, endOfDay bool) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// Where returns SQL conditions (each prefixed with AND) for the cursor and
+// date range, appending their values to args.
+func (p Page) Where(timeCol, idCol string, args *[]any) string {
+	var sb strings.Builder
+
+	if p.After != nil {
+		op := ">"
+		if p.Desc {
+			op = "<"
+		}
+		*args = append(*args, p.After.Time, p.After.ID)
+		fmt.Fprintf(&sb, " AND (%s, %s) %s ($%d, $%d)", timeCol, idCol, op, len(*args)-1, len(*args))
+	}
+	if p.From != nil {
+		*args = append(*args, *p.From)
+		fmt.Fprintf(&sb, " AND %s >= $%d", timeCol, len(*args))
+	}
+	if p.To != nil {
+		*args = append(*args, *p.To)
+		fmt.Fprintf(&sb, " AND %s < $%d", timeCol, len(*args))
+	}
+
+	return sb.String()
+}
+
+// OrderBy returns the ORDER BY and LIMIT clause. One extra row is fetched so
+// Trim can tell whether another page exists.
+func (p Page) OrderBy(timeCol, idCol string) string {
+	dir := "ASC"
+	if p.Desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, %s %s LIMIT %d", timeCol, dir, idCol, dir, p.Limit+1)
+}
+
+// Trim cuts items down to the page size and returns the cursor for the next
+// page, or "" on the last page.
+func Trim[T any](p Page, items []T, key func(T) (time.Time, string)) ([]T, string) {
+	if len(items) <= p.Limit {
+		return items, ""
+	}
+	items = items[:p.Limit]
+	t, id := key(items[len(items)-1])
+	return items, Cursor{Time: t, ID: id, Desc: p.Desc}.Encode()
+}
@@ -0,0 +1,206 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []Cursor{
+		{Time: time.Date(2026, 3, 29, 1, 30, 0, 0, time.UTC), ID: "51a1b2c3", Desc: true},
+		{Time: time.Date(2026, 1, 1, 0, 0, 0, 123456789, time.UTC), ID: "42", Desc: false},
+		{Time: time.Date(2025, 12, 31, 23, 59, 59, 0, time.FixedZone("IST", 5*3600+1800)), ID: "id/with+odd=chars", Desc: true},
+	}
+	for _, c := range tests {
+		s := c.Encode()
+		got, err := DecodeCursor(s)
+		if err != nil {
+			t.Fatalf("DecodeCursor(%q) error: %v", s, err)
+		}
+		if !got.Time.Equal(c.Time) || got.ID != c.ID || got.Desc != c.Desc {
+			t.Errorf("cursor %+v round-tripped to %+v", c, got)
+		}
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	b64 := base64.RawURLEncoding.EncodeToString
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"empty", ""},
+		{"not base64", "%%%"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"t":"2026-01-01T00:00:00Z","id":"1"}`))},
+		{"not json", b64([]byte("hello"))},
+		{"no id", b64([]byte(`{"t":"2026-01-01T00:00:00Z","d":true}`))},
+		{"bad time", b64([]byte(`{"t":"yesterday","id":"1"}`))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.in); err != ErrInvalidCursor {
+				t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", tt.in, err)
+			}
+		})
+	}
+}
+
+func TestFromRequest(t *testing.T) {
+	descCursor := Cursor{Time: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), ID: "p1", Desc: true}.Encode()
+	ascCursor := Cursor{Time: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), ID: "p1"}.Encode()
+	day := func(y int, m time.Month, d int) *time.Time {
+		t := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		return &t
+	}
+
+	tests := []struct {
+		query     string
+		want      Page
+		wantAfter bool
+		wantErr   bool
+	}{
+		{query: "", want: Page{Limit: DefaultLimit, Desc: true}},
+		{query: "limit=5&order=asc", want: Page{Limit: 5}},
+		{query: "limit=1000", want: Page{Limit: MaxLimit, Desc: true}},
+		{query: "order=DESC", want: Page{Limit: DefaultLimit, Desc: true}},
+		{query: "limit=0", wantErr: true},
+		{query: "limit=-1", wantErr: true},
+		{query: "limit=ten", wantErr: true},
+		{query: "order=sideways", wantErr: true},
+		{query: "cursor=" + descCursor, want: Page{Limit: DefaultLimit, Desc: true}, wantAfter: true},
+		{query: "order=asc&cursor=" + ascCursor, want: Page{Limit: DefaultLimit}, wantAfter: true},
+		{query: "order=asc&cursor=" + descCursor, wantErr: true},
+		{query: "cursor=garbage", wantErr: true},
+		// A bare "to" date covers that whole day.
+		{query: "from=2026-05-01&to=2026-05-31", want: Page{Limit: DefaultLimit, Desc: true, From: day(2026, 5, 1), To: day(2026, 6, 1)}},
+		{query: "to=2026-12-31", want: Page{Limit: DefaultLimit, Desc: true, To: day(2027, 1, 1)}},
+		{query: "from=2026-05-01T10:00:00Z", want: Page{Limit: DefaultLimit, Desc: true, From: func() *time.Time {
+			t := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+			return &t
+		}()}},
+		{query: "from=May+1", wantErr: true},
+		{query: "to=2026-02-30", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			page, err := FromRequest(httptest.NewRequest("GET", "/?"+tt.query, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FromRequest error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if page.Limit != tt.want.Limit || page.Desc != tt.want.Desc {
+				t.Errorf("limit, desc = %d, %v; want %d, %v", page.Limit, page.Desc, tt.want.Limit, tt.want.Desc)
+			}
+			if (page.After != nil) != tt.wantAfter {
+				t.Errorf("after = %v, want set %v", page.After, tt.wantAfter)
+			}
+			if !sameTime(page.From, tt.want.From) || !sameTime(page.To, tt.want.To) {
+				t.Errorf("range = %v..%v, want %v..%v", page.From, page.To, tt.want.From, tt.want.To)
+			}
+		})
+	}
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func TestWhere(t *testing.T) {
+	at := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	from, to := at.AddDate(0, -1, 0), at.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name     string
+		page     Page
+		want     string
+		wantArgs []any
+	}{
+		{"nothing", Page{Limit: 20, Desc: true}, "", []any{"u"}},
+		{"desc cursor", Page{Desc: true, After: &Cursor{Time: at, ID: "p9"}},
+			" AND (t.at, t.id) < ($2, $3)", []any{"u", at, "p9"}},
+		{"asc cursor", Page{After: &Cursor{Time: at, ID: "p9"}},
+			" AND (t.at, t.id) > ($2, $3)", []any{"u", at, "p9"}},
+		{"range", Page{From: &from, To: &to},
+			" AND t.at >= $2 AND t.at < $3", []any{"u", from, to}},
+		{"everything", Page{Desc: true, After: &Cursor{Time: at, ID: "p9"}, From: &from, To: &to},
+			" AND (t.at, t.id) < ($2, $3) AND t.at >= $4 AND t.at < $5", []any{"u", at, "p9", from, to}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := []any{"u"}
+			if got := tt.page.Where("t.at", "t.id", &args); got != tt.want {
+				t.Errorf("Where = %q, want %q", got, tt.want)
+			}
+			if !slices.Equal(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	if got, want := (Page{Limit: 20, Desc: true}).OrderBy("t.at", "t.id"), " ORDER BY t.at DESC, t.id DESC LIMIT 21"; got != want {
+		t.Errorf("OrderBy = %q, want %q", got, want)
+	}
+	if got, want := (Page{Limit: 5}).OrderBy("t.at", "t.id"), " ORDER BY t.at ASC, t.id ASC LIMIT 6"; got != want {
+		t.Errorf("OrderBy = %q, want %q", got, want)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	type row struct {
+		at time.Time
+		id string
+	}
+	base := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	rows := func(n int) []row {
+		out := make([]row, n)
+		for i := range out {
+			out[i] = row{base.Add(time.Duration(i) * time.Hour), string(rune('a' + i))}
+		}
+		return out
+	}
+	key := func(r row) (time.Time, string) { return r.at, r.id }
+
+	tests := []struct {
+		name     string
+		rows     int
+		limit    int
+		wantRows int
+		wantNext *Cursor
+	}{
+		{"empty", 0, 3, 0, nil},
+		{"short page", 2, 3, 2, nil},
+		{"exactly full", 3, 3, 3, nil},
+		{"one more", 4, 3, 3, &Cursor{Time: base.Add(2 * time.Hour), ID: "c", Desc: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, next := Trim(Page{Limit: tt.limit, Desc: true}, rows(tt.rows), key)
+			if len(got) != tt.wantRows {
+				t.Errorf("Trim kept %d rows, want %d", len(got), tt.wantRows)
+			}
+			if tt.wantNext == nil {
+				if next != "" {
+					t.Errorf("Trim next = %q on the last page", next)
+				}
+				return
+			}
+			c, err := DecodeCursor(next)
+			if err != nil {
+				t.Fatalf("Trim next = %q: %v", next, err)
+			}
+			if !c.Time.Equal(tt.wantNext.Time) || c.ID != tt.wantNext.ID || c.Desc != tt.wantNext.Desc {
+				t.Errorf("Trim next = %+v, want %+v", c, *tt.wantNext)
+			}
+		})
+	}
+}